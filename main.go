@@ -16,8 +16,10 @@ func main() {
 				rules.NewTerraformAnyTypeVariables(),
 				rules.NewTerraformRequiredTags(),
 				rules.NewTerraformModuleSourceVersion(),
+				rules.NewTerraformModuleVersionConsistency(),
 				rules.NewTerraformVarsObjectKeysNamingConventions(),
 				rules.NewTerraformRequiredVariables(),
+				rules.NewTerraformVariableAttributesOrder(),
 			},
 		},
 	})
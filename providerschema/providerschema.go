@@ -0,0 +1,87 @@
+// Package providerschema reads the provider schemas printed by
+// `terraform providers schema -json`.
+package providerschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Schemas is the output of `terraform providers schema -json`. Only the attributes
+// and nested blocks of resources are decoded.
+type Schemas struct {
+	ProviderSchemas map[string]*ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema is the schema of a provider, keyed by its source address such as
+// "registry.terraform.io/hashicorp/aws".
+type ProviderSchema struct {
+	ResourceSchemas map[string]*Schema `json:"resource_schemas"`
+}
+
+// Schema is the schema of a resource type.
+type Schema struct {
+	Block *Block `json:"block"`
+}
+
+// Block is the body of a resource or a nested block.
+type Block struct {
+	Attributes map[string]json.RawMessage `json:"attributes"`
+	BlockTypes map[string]*NestedBlock    `json:"block_types"`
+}
+
+// NestedBlock is a nested block type in a block.
+type NestedBlock struct {
+	Block *Block `json:"block"`
+}
+
+// Read reads the schemas from the JSON file.
+func Read(path string) (*Schemas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := &Schemas{}
+	if err := json.Unmarshal(data, schemas); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if schemas.ProviderSchemas == nil {
+		return nil, fmt.Errorf("%s has no `provider_schemas`, which is printed by `terraform providers schema -json`", path)
+	}
+	return schemas, nil
+}
+
+// Resource returns the schema of the resource type, and false if no provider in the
+// schemas has the resource type.
+func (s *Schemas) Resource(resourceType string) (*Block, bool) {
+	for _, provider := range s.ProviderSchemas {
+		if schema, exists := provider.ResourceSchemas[resourceType]; exists && schema.Block != nil {
+			return schema.Block, true
+		}
+	}
+	return nil, false
+}
+
+// HasPath returns whether the block has the path of nested blocks, ending with either
+// an attribute or a nested block.
+func (b *Block) HasPath(path []string) bool {
+	block := b
+	for i, name := range path {
+		if block == nil {
+			return false
+		}
+		if i == len(path)-1 {
+			if _, exists := block.Attributes[name]; exists {
+				return true
+			}
+		}
+		nested, exists := block.BlockTypes[name]
+		if !exists {
+			return false
+		}
+		block = nested.Block
+	}
+	return len(path) > 0
+}
@@ -0,0 +1,190 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// lineTokens indexes the tokens of a file by line, so that the file is lexed only
+// once and each line is looked up in constant time.
+type lineTokens struct {
+	filename string
+	// lines are the source lines including the trailing newline character.
+	lines []string
+	// lineStarts are the byte offsets of the beginning of each line.
+	lineStarts []int
+	// firstTokens are the types of the first token starting at each line.
+	firstTokens []hclsyntax.TokenType
+	// newLines marks empty new lines.
+	newLines []bool
+}
+
+func newLineTokens(src []byte, filename string) *lineTokens {
+	lines := strings.SplitAfter(string(src), "\n")
+	t := &lineTokens{
+		filename:    filename,
+		lines:       lines,
+		lineStarts:  make([]int, len(lines)+1),
+		firstTokens: make([]hclsyntax.TokenType, len(lines)+1),
+		newLines:    make([]bool, len(lines)+1),
+	}
+	for i, line := range lines {
+		t.lineStarts[i+1] = t.lineStarts[i] + len(line)
+	}
+
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	seenStart := make([]bool, len(lines)+1)
+	seenEnd := make([]bool, len(lines)+1)
+	for i, token := range tokens {
+		if line := token.Range.Start.Line; line <= len(lines) && !seenStart[line] {
+			seenStart[line] = true
+			t.firstTokens[line] = token.Type
+		}
+		// Empty new lines are expected to have two TokenNewLine continuously.
+		if line := token.Range.End.Line; line <= len(lines) && token.Range.End.Column == 1 && !seenEnd[line] {
+			seenEnd[line] = true
+			t.newLines[line] = i+1 < len(tokens) && token.Type == hclsyntax.TokenNewline && tokens[i+1].Type == hclsyntax.TokenNewline
+		}
+	}
+	return t
+}
+
+func (t *lineTokens) isNewLine(checkLine int) bool {
+	return checkLine > 0 && checkLine < len(t.newLines) && t.newLines[checkLine]
+}
+
+func (t *lineTokens) isCommentLine(line int) bool {
+	return line > 0 && line < len(t.firstTokens) && t.firstTokens[line] == hclsyntax.TokenComment
+}
+
+func (t *lineTokens) countCommentLinesForward(startingLine int) int {
+	var commentLinesCount int
+	for t.isCommentLine(startingLine + commentLinesCount) {
+		commentLinesCount++
+	}
+	return commentLinesCount
+}
+
+func (t *lineTokens) countCommentLinesBackward(startingLine int) int {
+	var commentLinesCount int
+	for t.isCommentLine(startingLine - commentLinesCount) {
+		commentLinesCount++
+	}
+	return commentLinesCount
+}
+
+// bodySegment is a group of consecutive lines inside a block body, which is either an
+// attribute/block with its leading comment lines, or comment lines that are not
+// attached to any attribute/block.
+type bodySegment struct {
+	// name is the attribute name or the block type, it is empty for comment lines.
+	name        string
+	startLine   int
+	endLine     int
+	blankBefore bool
+}
+
+// bodySegments splits the lines of the block body into segments sorted by their
+// position. It returns false if any attribute/block shares a line with the braces of
+// the block, which cannot be moved line by line.
+func (t *lineTokens) bodySegments(body *hclsyntax.Body) ([]bodySegment, bool) {
+	// Body lines are located between the line of opening brace and the line of closing brace.
+	firstLine := body.SrcRange.Start.Line + 1
+	lastLine := body.EndRange.Start.Line - 1
+	if firstLine > lastLine || lastLine > len(t.lines) {
+		return nil, false
+	}
+
+	var items []bodySegment
+	for name, attr := range body.Attributes {
+		items = append(items, bodySegment{name: name, startLine: attr.SrcRange.Start.Line, endLine: attr.SrcRange.End.Line})
+	}
+	for _, block := range body.Blocks {
+		items = append(items, bodySegment{name: block.Type, startLine: block.Range().Start.Line, endLine: block.Range().End.Line})
+	}
+
+	covered := make(map[int]bool)
+	for i, item := range items {
+		if item.startLine < firstLine || item.endLine > lastLine {
+			return nil, false
+		}
+		// Comment lines right above the attribute/block are moved together with it.
+		items[i].startLine -= t.countCommentLinesBackward(item.startLine - 1)
+		if items[i].startLine < firstLine {
+			items[i].startLine = firstLine
+		}
+		for line := items[i].startLine; line <= item.endLine; line++ {
+			covered[line] = true
+		}
+	}
+
+	// Remaining non-empty lines are comments which are not attached to any attribute/block.
+	for line := firstLine; line <= lastLine; line++ {
+		if covered[line] || t.isBlankLine(line) {
+			continue
+		}
+		segment := bodySegment{startLine: line, endLine: line}
+		for segment.endLine+1 <= lastLine && !covered[segment.endLine+1] && !t.isBlankLine(segment.endLine+1) {
+			segment.endLine++
+		}
+		items = append(items, segment)
+		line = segment.endLine
+	}
+
+	slices.SortFunc(items, func(a, b bodySegment) int { return a.startLine - b.startLine })
+	for i := range items {
+		items[i].blankBefore = items[i].startLine > firstLine && t.isBlankLine(items[i].startLine-1)
+	}
+	return items, true
+}
+
+// segmentText returns the source lines of the segment including the trailing newline character.
+func (t *lineTokens) segmentText(segment bodySegment) string {
+	return strings.Join(t.lines[segment.startLine-1:segment.endLine], "")
+}
+
+// bodyLinesRange returns the range from the beginning of the first body line until the
+// beginning of the closing brace line, which is replaced when the body is rearranged.
+func (t *lineTokens) bodyLinesRange(body *hclsyntax.Body) hcl.Range {
+	firstLine := body.SrcRange.Start.Line + 1
+	lastLine := body.EndRange.Start.Line - 1
+	start := hcl.Pos{Line: firstLine, Column: 1, Byte: t.lineStarts[firstLine-1]}
+	end := hcl.Pos{Line: lastLine + 1, Column: 1, Byte: t.lineStarts[lastLine]}
+	return hcl.Range{Filename: t.filename, Start: start, End: end}
+}
+
+func (t *lineTokens) isBlankLine(line int) bool {
+	return line > 0 && line <= len(t.lines) && strings.TrimSpace(t.lines[line-1]) == ""
+}
+
+// orderedRanks finds the longest subsequence of the ranks which is not decreasing,
+// preferring the ranks placed earlier, and marks the ranks in the subsequence. The
+// ranks out of the subsequence are the misplaced ones.
+func orderedRanks(ranks []int) []bool {
+	// lengths[i] is the length of the longest ordered subsequence starting at ranks[i].
+	lengths := make([]int, len(ranks))
+	longest := 0
+	for i := len(ranks) - 1; i >= 0; i-- {
+		lengths[i] = 1
+		for j := i + 1; j < len(ranks); j++ {
+			if ranks[j] >= ranks[i] && lengths[j]+1 > lengths[i] {
+				lengths[i] = lengths[j] + 1
+			}
+		}
+		longest = max(longest, lengths[i])
+	}
+
+	ordered := make([]bool, len(ranks))
+	previous := -1
+	for i := range ranks {
+		if lengths[i] == longest && (previous < 0 || ranks[i] >= ranks[previous]) {
+			ordered[i] = true
+			previous = i
+			longest--
+		}
+	}
+	return ordered
+}
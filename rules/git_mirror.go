@@ -0,0 +1,138 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gitRefKind is the kind of object a git ref resolves to.
+type gitRefKind int
+
+const (
+	gitRefNotFound gitRefKind = iota
+	gitRefTag
+	gitRefBranch
+	gitRefCommit
+)
+
+// gitCommitPattern matches abbreviated and full commit hashes.
+var gitCommitPattern = regexp.MustCompile(`^[0-9a-fA-F]{4,40}$`)
+
+// gitMirror resolves refs in bare repositories mirrored under a local directory, laid
+// out by host and path of the repository URL, such as
+// <dir>/gitlab.example.com/group/module.git. Only the git command line is used, so
+// that no network access is required.
+type gitMirror struct {
+	dir       string
+	cache     map[string]gitRefKind
+	tagsCache map[string][]string
+}
+
+func newGitMirror(dir string) *gitMirror {
+	return &gitMirror{dir: dir, cache: make(map[string]gitRefKind), tagsCache: make(map[string][]string)}
+}
+
+// repository returns the path of the bare repository mirroring the URL, with or
+// without the .git suffix. It returns false if the repository is not mirrored, or the
+// path escapes the mirror directory, such as git::https://host/../../repo.git.
+func (m *gitMirror) repository(u *url.URL) (string, bool) {
+	// Subdirectories in the repository such as repo.git//modules/vpc are not a part of the repository.
+	repoPath, _, _ := strings.Cut(u.Path, "//")
+	repoPath = strings.Trim(repoPath, "/")
+	if u.Hostname() == "" || repoPath == "" {
+		return "", false
+	}
+
+	base := filepath.Join(m.dir, u.Hostname(), filepath.FromSlash(repoPath))
+	if rel, err := filepath.Rel(m.dir, base); err != nil || !filepath.IsLocal(rel) {
+		return "", false
+	}
+	for _, candidate := range []string{base, strings.TrimSuffix(base, ".git") + ".git"} {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// resolveRef returns the kind of object the ref resolves to in the repository. Tags
+// take precedence over branches with the same name, and only hexadecimal refs are
+// resolved as commits. An error is returned if git fails for any other reason than the
+// ref not existing, such as git not being installed.
+func (m *gitMirror) resolveRef(repo string, ref string) (gitRefKind, error) {
+	key := repo + "\x00" + ref
+	if kind, exists := m.cache[key]; exists {
+		return kind, nil
+	}
+
+	kind, err := m.lookupRef(repo, ref)
+	if err != nil {
+		return gitRefNotFound, err
+	}
+	m.cache[key] = kind
+	return kind, nil
+}
+
+// lookupRef resolves the ref as a tag, a branch and a commit in order.
+func (m *gitMirror) lookupRef(repo string, ref string) (gitRefKind, error) {
+	if exists, err := m.revParse(repo, "refs/tags/"+ref); err != nil || exists {
+		return gitRefTag, err
+	}
+	if exists, err := m.revParse(repo, "refs/heads/"+ref); err != nil || exists {
+		return gitRefBranch, err
+	}
+	if gitCommitPattern.MatchString(ref) {
+		if exists, err := m.revParse(repo, ref); err != nil || exists {
+			return gitRefCommit, err
+		}
+	}
+	return gitRefNotFound, nil
+}
+
+// revParse returns whether the revision resolves to a commit. `git rev-parse --verify
+// --quiet` exits with 1 only if the revision does not exist.
+func (m *gitMirror) revParse(repo string, rev string) (bool, error) {
+	cmd := exec.Command("git", "--git-dir", repo, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, gitError(repo, err, out)
+}
+
+// tags returns the names of all tags in the repository.
+func (m *gitMirror) tags(repo string) ([]string, error) {
+	if tags, exists := m.tagsCache[repo]; exists {
+		return tags, nil
+	}
+
+	out, err := exec.Command("git", "--git-dir", repo, "tag", "--list").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, gitError(repo, err, exitErr.Stderr)
+		}
+		return nil, gitError(repo, err, nil)
+	}
+	tags := strings.Fields(string(out))
+	m.tagsCache[repo] = tags
+	return tags, nil
+}
+
+// gitError wraps the error of the git command with its output, if any.
+func gitError(repo string, err error, out []byte) error {
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("failed to run git in %s: %w: %s", repo, err, msg)
+	}
+	return fmt.Errorf("failed to run git in %s: %w", repo, err)
+}
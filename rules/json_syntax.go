@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/terraform-linters/tflint-plugin-sdk/terraform"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// isJSONSyntax returns whether the file is written in JSON syntax (.tf.json). JSON
+// syntax files do not have any layout to check, such as new lines and comments.
+func isJSONSyntax(filename string) bool {
+	return terraform.IsJSONFilename(filename)
+}
+
+// nativeExpr converts the expression into native syntax. A string in JSON syntax is
+// interpreted as a template like Terraform does, and a template with a single
+// interpolation such as "${merge(local.tags, {})}" is unwrapped into the interpolated
+// expression. It returns false if the expression cannot be converted, such as an object
+// or a list in JSON syntax.
+func nativeExpr(runner tflint.Runner, expr hcl.Expression) (hclsyntax.Expression, bool) {
+	if syntaxExpr, ok := expr.(hclsyntax.Expression); ok {
+		return syntaxExpr, true
+	}
+
+	str, start, ok := jsonStringContent(runner, expr)
+	if !ok {
+		return nil, false
+	}
+	tmplExpr, diags := hclsyntax.ParseTemplate([]byte(str), start.Filename, start.Start)
+	if diags.HasErrors() {
+		return nil, false
+	}
+	if wrapExpr, ok := tmplExpr.(*hclsyntax.TemplateWrapExpr); ok {
+		return wrapExpr.Wrapped, true
+	}
+	return tmplExpr, true
+}
+
+// nativeTypeExpr converts the type constraint expression into native syntax. Type
+// constraints in JSON syntax are written as a string containing the native syntax
+// expression, such as "map(string)".
+func nativeTypeExpr(runner tflint.Runner, expr hcl.Expression) (hclsyntax.Expression, bool) {
+	if syntaxExpr, ok := expr.(hclsyntax.Expression); ok {
+		return syntaxExpr, true
+	}
+
+	str, start, ok := jsonStringContent(runner, expr)
+	if !ok {
+		return nil, false
+	}
+	typeExpr, diags := hclsyntax.ParseExpression([]byte(str), start.Filename, start.Start)
+	if diags.HasErrors() {
+		return nil, false
+	}
+	return typeExpr, true
+}
+
+// literalValue returns the value of the expression if it is a literal value, such as
+// `true` in native syntax or JSON syntax.
+func literalValue(expr hcl.Expression) (cty.Value, bool) {
+	if literalExpr, ok := expr.(*hclsyntax.LiteralValueExpr); ok {
+		return literalExpr.Val, true
+	}
+	if _, ok := expr.(hclsyntax.Expression); ok || len(expr.Variables()) > 0 {
+		return cty.NilVal, false
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+// jsonStringContent returns the unquoted content of a string expression in JSON syntax,
+// and the empty range where the content starts, right after the opening quote.
+func jsonStringContent(runner tflint.Runner, expr hcl.Expression) (string, hcl.Range, bool) {
+	rng := expr.Range()
+	file, err := runner.GetFile(rng.Filename)
+	if err != nil || file == nil || rng.End.Byte > len(file.Bytes) {
+		return "", hcl.Range{}, false
+	}
+
+	var str string
+	if err := json.Unmarshal(file.Bytes[rng.Start.Byte:rng.End.Byte], &str); err != nil {
+		return "", hcl.Range{}, false
+	}
+
+	start := hcl.Pos{Line: rng.Start.Line, Column: rng.Start.Column + 1, Byte: rng.Start.Byte + 1}
+	return str, hcl.Range{Filename: rng.Filename, Start: start, End: start}, true
+}
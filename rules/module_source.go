@@ -0,0 +1,46 @@
+package rules
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+)
+
+// moduleSourceDetectors are the go-getter detectors normalizing module sources into URLs.
+var moduleSourceDetectors = []getter.Detector{
+	new(getter.GitHubDetector),
+	new(getter.GitDetector),
+	new(getter.S3Detector),
+	new(getter.GCSDetector),
+	new(getter.FileDetector),
+}
+
+// parseModuleSourceURL parses the module source normalized by getter.Detect, and returns
+// the getter with the URL. The forced getter such as git in git::https://... is parsed as
+// the scheme, and the actual URL is in the opaque part.
+func parseModuleSourceURL(source string) (string, *url.URL, error) {
+	u, err := url.ParseRequestURI(source)
+	if err != nil {
+		return "", nil, err
+	}
+
+	scheme := u.Scheme
+	if u.Opaque != "" {
+		query := u.RawQuery
+		u, err = url.Parse(strings.TrimPrefix(u.Opaque, ":"))
+		if err != nil {
+			return "", nil, err
+		}
+		u.RawQuery = query
+	}
+	return scheme, u, nil
+}
+
+// moduleSourceRepository returns the repository of the module source URL, which is the
+// host and path without the .git suffix and the subdirectory such as repo.git//modules/vpc.
+func moduleSourceRepository(u *url.URL) string {
+	repoPath, _, _ := strings.Cut(u.Path, "//")
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	return strings.ToLower(u.Host) + "/" + repoPath
+}
@@ -0,0 +1,129 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/Masterminds/semver"
+)
+
+// terraformModuleVersionPolicyConfig is a `version_policy` block of the
+// terraform_module_source_version rule. It applies to the module sources matching the
+// `source` pattern.
+type terraformModuleVersionPolicyConfig struct {
+	Source             string            `hclext:"source"`
+	MinimumVersion     string            `hclext:"minimum_version,optional"`
+	BlockedVersions    []string          `hclext:"blocked_versions,optional"`
+	DeprecatedVersions map[string]string `hclext:"deprecated_versions,optional"`
+}
+
+// moduleVersionPolicy is a compiled `version_policy` block.
+type moduleVersionPolicy struct {
+	source     *regexp.Regexp
+	minimum    *semver.Version
+	blocked    []*semver.Constraints
+	deprecated []deprecatedModuleVersion
+}
+
+// deprecatedModuleVersion is an entry of `deprecated_versions`, the constraint and the
+// message explaining why the versions are deprecated.
+type deprecatedModuleVersion struct {
+	constraint *semver.Constraints
+	message    string
+}
+
+// newModuleVersionPolicies compiles the `version_policy` blocks in the configured order.
+func newModuleVersionPolicies(configs []terraformModuleVersionPolicyConfig) ([]*moduleVersionPolicy, error) {
+	policies := make([]*moduleVersionPolicy, 0, len(configs))
+	for i, config := range configs {
+		policy := &moduleVersionPolicy{}
+		key := fmt.Sprintf("version_policy[%d]", i)
+
+		var err error
+		if policy.source, err = regexp.Compile(config.Source); err != nil {
+			return nil, configErrorf(key+".source", "%s", err)
+		}
+		if config.MinimumVersion != "" {
+			if policy.minimum, err = semver.NewVersion(config.MinimumVersion); err != nil {
+				return nil, configErrorf(key+".minimum_version", "`%s`: %s", config.MinimumVersion, err)
+			}
+		}
+		for j, blocked := range config.BlockedVersions {
+			constraint, err := semver.NewConstraint(blocked)
+			if err != nil {
+				return nil, configErrorf(fmt.Sprintf("%s.blocked_versions[%d]", key, j), "`%s`: %s", blocked, err)
+			}
+			policy.blocked = append(policy.blocked, constraint)
+		}
+		// Map keys are sorted, so that the same message is reported on every run.
+		for _, deprecated := range slices.Sorted(maps.Keys(config.DeprecatedVersions)) {
+			constraint, err := semver.NewConstraint(deprecated)
+			if err != nil {
+				return nil, configErrorf(fmt.Sprintf("%s.deprecated_versions[%q]", key, deprecated), "%s", err)
+			}
+			policy.deprecated = append(policy.deprecated, deprecatedModuleVersion{constraint: constraint, message: config.DeprecatedVersions[deprecated]})
+		}
+
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// findModuleVersionPolicy returns the first policy matching the module source.
+func findModuleVersionPolicy(policies []*moduleVersionPolicy, source string) (*moduleVersionPolicy, bool) {
+	for _, policy := range policies {
+		if policy.source.MatchString(source) {
+			return policy, true
+		}
+	}
+	return nil, false
+}
+
+// violation returns the reason why the version is not allowed by the policy, or an
+// empty string if the version is allowed.
+func (p *moduleVersionPolicy) violation(version *semver.Version) string {
+	for _, blocked := range p.blocked {
+		if blocked.Check(version) {
+			return "is blocked"
+		}
+	}
+	if p.minimum != nil && version.LessThan(p.minimum) {
+		return fmt.Sprintf("is older than the minimum version '%s'", p.minimum.Original())
+	}
+	for _, deprecated := range p.deprecated {
+		if deprecated.constraint.Check(version) {
+			return fmt.Sprintf("is deprecated: %s", deprecated.message)
+		}
+	}
+	return ""
+}
+
+// newest returns the newest version allowed by the policy among the candidates, such
+// as the tags of a repository. Prereleases and non semantic versions are never suggested.
+func (p *moduleVersionPolicy) newest(candidates []string) (string, bool) {
+	var newest *semver.Version
+	for _, candidate := range candidates {
+		version, err := semver.NewVersion(candidate)
+		if err != nil || version.Prerelease() != "" || p.violation(version) != "" {
+			continue
+		}
+		if newest == nil || version.GreaterThan(newest) {
+			newest = version
+		}
+	}
+	if newest == nil {
+		return "", false
+	}
+	return newest.Original(), true
+}
+
+// acceptableMinimum returns the minimum version if the version is older than it, and the
+// minimum version itself is allowed by the policy.
+func (p *moduleVersionPolicy) acceptableMinimum(version *semver.Version) (string, bool) {
+	if p.minimum == nil || !version.LessThan(p.minimum) || p.violation(p.minimum) != "" {
+		return "", false
+	}
+	return p.minimum.Original(), true
+}
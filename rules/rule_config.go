@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// ruleConfigValidator is implemented by rule configs which need to be checked after
+// decoding, such as regular expressions and values of enums.
+type ruleConfigValidator interface {
+	// validate checks the decoded config, and prepares the values used by the rule
+	// such as defaults and compiled regular expressions.
+	validate() error
+}
+
+// ruleConfigError is an error of a key in the rule config.
+type ruleConfigError struct {
+	key string
+	err error
+}
+
+func (e *ruleConfigError) Error() string {
+	return fmt.Sprintf("invalid `%s`: %s", e.key, e.err)
+}
+
+func (e *ruleConfigError) Unwrap() error {
+	return e.err
+}
+
+// configErrorf returns an error of the key in the rule config.
+func configErrorf(key string, format string, args ...any) error {
+	return &ruleConfigError{key: key, err: fmt.Errorf(format, args...)}
+}
+
+// decodeRuleConfig decodes the config of the rule and validates it, so that an invalid
+// config fails the rule before checking any module, rather than in the middle of it.
+// Unknown keys are rejected by decoding, because the schema is implied by the config.
+func decodeRuleConfig(runner tflint.Runner, rule tflint.Rule, config any) error {
+	if err := runner.DecodeRuleConfig(rule.Name(), config); err != nil {
+		return fmt.Errorf("rule `%s`: %w", rule.Name(), err)
+	}
+
+	if validator, ok := config.(ruleConfigValidator); ok {
+		if err := validator.validate(); err != nil {
+			return fmt.Errorf("rule `%s`: %w", rule.Name(), err)
+		}
+	}
+	return nil
+}
+
+// compileRegexps compiles the regular expressions of the key in the rule config.
+func compileRegexps(key string, patterns []string) ([]*regexp.Regexp, error) {
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, configErrorf(fmt.Sprintf("%s[%d]", key, i), "%s", err)
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+// validateGlobs checks the glob patterns of the key in the rule config.
+func validateGlobs(key string, patterns []string) error {
+	for i, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return configErrorf(fmt.Sprintf("%s[%d]", key, i), "`%s`: %s", pattern, err)
+		}
+	}
+	return nil
+}
+
+// validateOneOf checks whether the value of the key in the rule config is one of the values.
+func validateOneOf(key string, value string, values []string) error {
+	if slices.Contains(values, value) {
+		return nil
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("`%s`", v)
+	}
+	return configErrorf(key, "`%s` is not supported, must be one of %s", value, strings.Join(quoted, ", "))
+}
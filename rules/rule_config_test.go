@@ -0,0 +1,235 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/terraform-linters/tflint-plugin-sdk/helper"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+func Test_DecodeRuleConfig(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Rule   tflint.Rule
+		Config string
+		Error  string
+	}{
+		{
+			Name: "unknown key",
+			Rule: NewTerraformAnyTypeVariables(),
+			Config: `
+rule "terraform_any_type_variables" {
+  enabled     = true
+  ignore_var = ["my_var"]
+}`,
+			Error: "rule `terraform_any_type_variables`: .tflint.hcl:4,3-13: Unsupported argument; An argument named \"ignore_var\" is not expected here. Did you mean \"ignore_vars\"?",
+		},
+		{
+			Name: "invalid regex in allowed_versions",
+			Rule: NewTerraformModuleSourceVersion(),
+			Config: `
+rule "terraform_module_source_version" {
+  enabled          = true
+  allowed_versions = ["^feature/\\d+$", "^bugfix/(\\d+$"]
+}`,
+			Error: "rule `terraform_module_source_version`: invalid `allowed_versions[1]`: error parsing regexp: missing closing ): `^bugfix/(\\d+$`",
+		},
+		{
+			Name: "unsupported pin_style",
+			Rule: NewTerraformModuleSourceVersion(),
+			Config: `
+rule "terraform_module_source_version" {
+  enabled   = true
+  pin_style = "branch"
+}`,
+			Error: "rule `terraform_module_source_version`: invalid `pin_style`: `branch` is not supported, must be one of `tag`, `sha`, `tag_or_full_sha`",
+		},
+		{
+			Name: "unsupported operator in allowed_version_operators",
+			Rule: NewTerraformModuleSourceVersion(),
+			Config: `
+rule "terraform_module_source_version" {
+  enabled                   = true
+  allowed_version_operators = ["=", "=>"]
+}`,
+			Error: "rule `terraform_module_source_version`: invalid `allowed_version_operators[1]`: `=>` is not supported, must be one of `=`, `!=`, `>=`, `<=`, `>`, `<`, `~>`",
+		},
+		{
+			Name: "invalid minimum_version in version_policy",
+			Rule: NewTerraformModuleSourceVersion(),
+			Config: `
+rule "terraform_module_source_version" {
+  enabled = true
+
+  version_policy {
+    source          = "gitlab\\.example\\.com"
+    minimum_version = "latest"
+  }
+}`,
+			Error: "rule `terraform_module_source_version`: invalid `version_policy[0].minimum_version`: `latest`: Invalid Semantic Version",
+		},
+		{
+			Name: "unsupported format",
+			Rule: NewTerraformVarsObjectKeysNamingConventions(),
+			Config: `
+rule "terraform_vars_object_keys_naming_conventions" {
+  enabled = true
+  format  = "kebab_case"
+}`,
+			Error: "rule `terraform_vars_object_keys_naming_conventions`: invalid `format`: `kebab_case` is not supported, must be one of `mixed_snake_case`, `snake_case`, `none`",
+		},
+		{
+			Name: "invalid regex in custom_formats",
+			Rule: NewTerraformVarsObjectKeysNamingConventions(),
+			Config: `
+rule "terraform_vars_object_keys_naming_conventions" {
+  enabled           = true
+  custom_format_key = "PascalCase"
+  custom_formats = {
+    PascalCase = {
+      regex       = "^[A-Z][a-zA-Z0-9]*$"
+      description = "PascalCase"
+    }
+    Broken = {
+      regex       = "^[a-z"
+      description = "Broken"
+    }
+  }
+}`,
+			Error: "rule `terraform_vars_object_keys_naming_conventions`: invalid `custom_formats[\"Broken\"].regex`: error parsing regexp: missing closing ]: `[a-z`",
+		},
+		{
+			Name: "undefined custom_format_key",
+			Rule: NewTerraformVarsObjectKeysNamingConventions(),
+			Config: `
+rule "terraform_vars_object_keys_naming_conventions" {
+  enabled           = true
+  custom_format_key = "PascalCase"
+}`,
+			Error: "rule `terraform_vars_object_keys_naming_conventions`: invalid `custom_format_key`: `PascalCase` is not defined in custom_formats",
+		},
+		{
+			Name: "invalid type of required variable",
+			Rule: NewTerraformRequiredVariables(),
+			Config: `
+rule "terraform_required_variables" {
+  enabled = true
+
+  variable "module_info" {
+    type = "object(string)"
+  }
+}`,
+			Error: "rule `terraform_required_variables`: invalid `variable[\"module_info\"].type`: `object(string)`: Invalid type specification; Object type constructor requires a map whose keys are attribute names and whose values are the corresponding attribute types.",
+		},
+		{
+			Name: "invalid regex in sensitive_var_patterns",
+			Rule: NewTerraformRequiredVariables(),
+			Config: `
+rule "terraform_required_variables" {
+  enabled                = true
+  sensitive_var_patterns = [".*_password", "*_token"]
+}`,
+			Error: "rule `terraform_required_variables`: invalid `sensitive_var_patterns[1]`: error parsing regexp: missing argument to repetition operator: `*`",
+		},
+		{
+			Name: "invalid regex in tag_values",
+			Rule: NewTerraformRequiredTags(),
+			Config: `
+rule "terraform_required_tags" {
+  enabled = true
+
+  tag_values "brand" {
+    regex = "^[a-z{2,8}$"
+  }
+}`,
+			Error: "rule `terraform_required_tags`: invalid `tag_values[\"brand\"].regex`: error parsing regexp: missing closing ]: `[a-z{2,8}$`",
+		},
+		{
+			Name: "both values and regex in tag_values",
+			Rule: NewTerraformRequiredTags(),
+			Config: `
+rule "terraform_required_tags" {
+  enabled = true
+
+  tag_values "env" {
+    values = ["prod", "dev"]
+    regex  = "^(prod|dev)$"
+  }
+}`,
+			Error: "rule `terraform_required_tags`: invalid `tag_values[\"env\"]`: only one of `values` and `regex` can be set",
+		},
+		{
+			Name: "unsupported format in tag_attribute",
+			Rule: NewTerraformRequiredTags(),
+			Config: `
+rule "terraform_required_tags" {
+  enabled = true
+
+  tag_attribute "kubernetes_" {
+    path   = "metadata.labels"
+    format = "object"
+  }
+}`,
+			Error: "rule `terraform_required_tags`: invalid `tag_attribute[\"kubernetes_\"].format`: `object` is not supported, must be one of `map`, `list`, `block`",
+		},
+		{
+			Name: "missing providers_schema_file",
+			Rule: NewTerraformRequiredTags(),
+			Config: `
+rule "terraform_required_tags" {
+  enabled               = true
+  providers_schema_file = "missing.json"
+}`,
+			Error: "rule `terraform_required_tags`: invalid `providers_schema_file`: open missing.json: no such file or directory",
+		},
+		{
+			Name: "unsupported block type in order",
+			Rule: NewTerraformMetaArguments(),
+			Config: `
+rule "terraform_meta_arguments" {
+  enabled = true
+  order = {
+    provider = ["alias"]
+  }
+}`,
+			Error: "rule `terraform_meta_arguments`: invalid `order`: `provider` is not supported, must be one of `module`, `resource`, `data`",
+		},
+		{
+			Name: "unsupported attribute in order",
+			Rule: NewTerraformVariableAttributesOrder(),
+			Config: `
+rule "terraform_variable_attributes_order" {
+  enabled = true
+  order   = ["type", "descripton"]
+}`,
+			Error: "rule `terraform_variable_attributes_order`: invalid `order[1]`: `descripton` is not supported, must be one of `type`, `description`, `default`, `sensitive`, `nullable`, `ephemeral`, `validation`",
+		},
+		{
+			Name: "duplicate attribute in order",
+			Rule: NewTerraformVariableAttributesOrder(),
+			Config: `
+rule "terraform_variable_attributes_order" {
+  enabled = true
+  order   = ["type", "description", "type"]
+}`,
+			Error: "rule `terraform_variable_attributes_order`: invalid `order[2]`: `type` is listed more than once",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf":     "",
+				".tflint.hcl": test.Config,
+			})
+
+			err := test.Rule.Check(runner)
+			if err == nil {
+				t.Fatalf("Expected error `%s`, but got nil", test.Error)
+			}
+			if err.Error() != test.Error {
+				t.Fatalf("Expected error `%s`, but got `%s`", test.Error, err.Error())
+			}
+		})
+	}
+}
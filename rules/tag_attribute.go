@@ -0,0 +1,135 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+)
+
+// Formats of the tag attribute of resources.
+const (
+	// tagFormatMap is an object of tag keys and values, such as `tags = { env = "prod" }`.
+	tagFormatMap = "map"
+	// tagFormatList is a list of "key:value" strings, such as `tags = ["env:prod"]`.
+	tagFormatList = "list"
+	// tagFormatBlock is a block for each tag with the `key` and `value` attributes,
+	// such as `tag { key = "env" value = "prod" }`.
+	tagFormatBlock = "block"
+)
+
+var tagFormats = []string{tagFormatMap, tagFormatList, tagFormatBlock}
+
+// terraformRequiredTagAttributeConfig is a `tag_attribute` block of the
+// terraform_required_tags rule. It applies to the resource types starting with the
+// prefix in the label.
+type terraformRequiredTagAttributeConfig struct {
+	Prefix    string `hclext:"prefix,label"`
+	Path      string `hclext:"path"`
+	Format    string `hclext:"format,optional"`
+	Lowercase bool   `hclext:"lowercase,optional"`
+}
+
+// tagAttribute is where the tags of a resource are set. The path is the nested
+// blocks followed by the attribute, or by the blocks in the block format.
+type tagAttribute struct {
+	path      []string
+	format    string
+	lowercase bool
+}
+
+// name returns the path of the tag attribute as written in the config.
+func (a *tagAttribute) name() string {
+	return strings.Join(a.path, ".")
+}
+
+// defaultTagAttributes are the tag attributes of the providers we use. Resources not
+// matching any prefix set tags in `tags`, either as a map or a list, which covers AWS,
+// Alibaba Cloud, Azure and OpenStack.
+var defaultTagAttributes = map[string]*tagAttribute{
+	"":            {path: []string{"tags"}},
+	"google_":     {path: []string{"labels"}, format: tagFormatMap, lowercase: true},
+	"kubernetes_": {path: []string{"metadata", "labels"}, format: tagFormatMap},
+}
+
+// newTagAttributes compiles the `tag_attribute` blocks, which override the default
+// tag attributes with the same prefix.
+func newTagAttributes(configs []terraformRequiredTagAttributeConfig) (map[string]*tagAttribute, error) {
+	attributes := maps.Clone(defaultTagAttributes)
+	configured := map[string]bool{}
+	for _, config := range configs {
+		key := fmt.Sprintf("tag_attribute[%q]", config.Prefix)
+		if configured[config.Prefix] {
+			return nil, configErrorf(key, "duplicate block for the prefix")
+		}
+		configured[config.Prefix] = true
+
+		path := strings.Split(config.Path, ".")
+		if slices.Contains(path, "") {
+			return nil, configErrorf(key+".path", "`%s` is not a valid path", config.Path)
+		}
+		if config.Format == "" {
+			config.Format = tagFormatMap
+		}
+		if err := validateOneOf(key+".format", config.Format, tagFormats); err != nil {
+			return nil, err
+		}
+
+		attributes[config.Prefix] = &tagAttribute{path: path, format: config.Format, lowercase: config.Lowercase}
+	}
+	return attributes, nil
+}
+
+// findTagAttribute returns the tag attribute with the longest prefix of the resource type.
+func findTagAttribute(attributes map[string]*tagAttribute, resourceType string) *tagAttribute {
+	var found *tagAttribute
+	longest := -1
+	for prefix, attribute := range attributes {
+		if strings.HasPrefix(resourceType, prefix) && len(prefix) > longest {
+			found, longest = attribute, len(prefix)
+		}
+	}
+	return found
+}
+
+// tagAttributesSchema returns the schema of resource bodies including all tag attributes.
+func tagAttributesSchema(attributes map[string]*tagAttribute) *hclext.BodySchema {
+	schema := &hclext.BodySchema{}
+	// Prefixes are sorted, so that the schema is the same on every run.
+	for _, prefix := range slices.Sorted(maps.Keys(attributes)) {
+		attribute := attributes[prefix]
+		body := schema
+		for _, blockType := range attribute.path[:len(attribute.path)-1] {
+			body = nestedBlockSchema(body, blockType)
+		}
+
+		name := attribute.path[len(attribute.path)-1]
+		if attribute.format == tagFormatBlock {
+			tagBody := nestedBlockSchema(body, name)
+			for _, tagAttr := range []string{"key", "value"} {
+				if !slices.ContainsFunc(tagBody.Attributes, func(s hclext.AttributeSchema) bool { return s.Name == tagAttr }) {
+					tagBody.Attributes = append(tagBody.Attributes, hclext.AttributeSchema{Name: tagAttr})
+				}
+			}
+			continue
+		}
+		if !slices.ContainsFunc(body.Attributes, func(s hclext.AttributeSchema) bool { return s.Name == name }) {
+			body.Attributes = append(body.Attributes, hclext.AttributeSchema{Name: name})
+		}
+	}
+	return schema
+}
+
+// nestedBlockSchema returns the body schema of the block type in the schema, adding
+// the block type if it is not in the schema yet.
+func nestedBlockSchema(schema *hclext.BodySchema, blockType string) *hclext.BodySchema {
+	for _, block := range schema.Blocks {
+		if block.Type == blockType {
+			return block.Body
+		}
+	}
+	schema.Blocks = append(schema.Blocks, hclext.BlockSchema{Type: blockType, Body: &hclext.BodySchema{}})
+	return schema.Blocks[len(schema.Blocks)-1].Body
+}
@@ -0,0 +1,693 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// tagResolver resolves the tags of expressions statically for a module. All local
+// variables are fetched once, and the tags of local variables are memoized, so that
+// the tags shared by many resources are resolved only once.
+type tagResolver struct {
+	runner tflint.Runner
+	locals map[string]*hclext.Attribute
+	// dependencies is the local variables referred by each local variable.
+	dependencies map[string][]string
+	// cycles is the local variables referring to each other in a cycle, which
+	// cannot be resolved.
+	cycles [][]string
+	cyclic map[string]bool
+	cache  map[string]resolvedTags
+}
+
+// resolvedTags is the memoized tags of a local variable.
+type resolvedTags struct {
+	tags     []resourceTag
+	resolved bool
+}
+
+func newTagResolver(runner tflint.Runner) (*tagResolver, error) {
+	content, err := runner.GetModuleContent(&hclext.BodySchema{
+		Blocks: []hclext.BlockSchema{
+			{
+				Type: "locals",
+				Body: &hclext.BodySchema{Mode: hclext.SchemaJustAttributesMode},
+			},
+		},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tagResolver{
+		runner:       runner,
+		locals:       map[string]*hclext.Attribute{},
+		dependencies: map[string][]string{},
+		cyclic:       map[string]bool{},
+		cache:        map[string]resolvedTags{},
+	}
+	for _, block := range content.Blocks {
+		maps.Copy(t.locals, block.Body.Attributes)
+	}
+
+	for name, attr := range t.locals {
+		var dependencies []string
+		for _, traversal := range attr.Expr.Variables() {
+			if traversal.RootName() != "local" || len(traversal) < 2 {
+				continue
+			}
+			if step, ok := traversal[1].(hcl.TraverseAttr); ok {
+				if _, exists := t.locals[step.Name]; exists {
+					dependencies = append(dependencies, step.Name)
+				}
+			}
+		}
+		slices.Sort(dependencies)
+		t.dependencies[name] = slices.Compact(dependencies)
+	}
+
+	t.cycles = t.localCycles()
+	for _, cycle := range t.cycles {
+		for _, name := range cycle {
+			t.cyclic[name] = true
+		}
+	}
+	return t, nil
+}
+
+// localCycles returns the local variables referring to each other in a cycle, which are
+// the strongly connected components of the dependency graph by Tarjan's algorithm.
+func (t *tagResolver) localCycles() [][]string {
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]string
+
+	var strongConnect func(name string)
+	strongConnect = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, dependency := range t.dependencies[name] {
+			if _, visited := index[dependency]; !visited {
+				strongConnect(dependency)
+				lowLink[name] = min(lowLink[name], lowLink[dependency])
+			} else if onStack[dependency] {
+				lowLink[name] = min(lowLink[name], index[dependency])
+			}
+		}
+
+		if lowLink[name] != index[name] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == name {
+				break
+			}
+		}
+		// A component of a single local variable is a cycle only if it refers to itself.
+		if len(component) > 1 || slices.Contains(t.dependencies[name], name) {
+			slices.Sort(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	// Local variables are visited in order, so that the cycles are the same on every run.
+	for _, name := range slices.Sorted(maps.Keys(t.locals)) {
+		if _, visited := index[name]; !visited {
+			strongConnect(name)
+		}
+	}
+	slices.SortFunc(cycles, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+	return cycles
+}
+
+// traverseSearchExpr performs a deep traverse into every nested local variables used,
+// check the value of tags and invoke different logics to evaluate. It returns false
+// if some of the tags cannot be resolved statically.
+func (t *tagResolver) traverseSearchExpr(expr hcl.Expression) ([]resourceTag, bool) {
+	if _, ok := expr.(hclsyntax.Expression); !ok {
+		return t.traverseSearchJSONExpr(expr)
+	}
+
+	// Check the value of tags and invoke different logics to evaluate.
+	switch expr := expr.(type) {
+	case *hclsyntax.FunctionCallExpr:
+		switch expr.Name {
+		// Usage of function calls like merge(local.tags, { ... }) or concat(local.tags, [...])
+		case "merge", "concat":
+			var tags []resourceTag
+			resolved := true
+			for _, arg := range expr.Args {
+				argTags, argResolved := t.traverseSearchExpr(arg)
+				tags = slices.Concat(tags, argTags)
+				resolved = resolved && argResolved
+			}
+			return tags, resolved
+		// Type conversions keep the tags of the argument.
+		case "tomap", "tolist":
+			if len(expr.Args) == 1 {
+				return t.traverseSearchExpr(expr.Args[0])
+			}
+		case "lookup":
+			return t.traverseSearchLookupExpr(expr)
+		}
+		return t.evaluateTags(expr)
+
+	// Use of local variable on tags, or an attribute of a local variable
+	// E.g. tags = local.tags, tags = local.common.tags
+	case *hclsyntax.ScopeTraversalExpr:
+		if expr.Traversal.RootName() == "local" {
+			return t.evaluateLocalVarTags(expr.Traversal)
+		}
+		return t.evaluateTags(expr)
+
+	// Only the tags in both branches are always set.
+	// E.g. tags = var.enabled ? merge(local.tags, { ... }) : local.tags
+	case *hclsyntax.ConditionalExpr:
+		trueTags, trueResolved := t.traverseSearchExpr(expr.TrueResult)
+		falseTags, falseResolved := t.traverseSearchExpr(expr.FalseResult)
+		return intersectTags(trueTags, falseTags), trueResolved && falseResolved
+
+	case *hclsyntax.ParenthesesExpr:
+		return t.traverseSearchExpr(expr.Expression)
+
+	// When it's actual object values in tags field, each key and value is resolved
+	// on its own, so that the keys are extracted even if some values are unknown.
+	case *hclsyntax.ObjectConsExpr:
+		var tags []resourceTag
+		resolved := true
+		for _, item := range expr.Items {
+			key := t.staticValue(item.KeyExpr)
+			if !key.IsWhollyKnown() || key.IsNull() || key.IsMarked() || key.Type() != cty.String {
+				resolved = false
+				continue
+			}
+			tags = append(tags, resourceTag{key: key.AsString(), value: t.staticValue(item.ValueExpr)})
+		}
+		return tags, resolved
+
+	// When it's actual list values in tags field, used in Openstack provider like
+	// compute_instance_v2. The key of "env:${var.env}" is extracted even if the
+	// value is unknown.
+	case *hclsyntax.TupleConsExpr:
+		var tags []resourceTag
+		resolved := true
+		for _, elem := range expr.Exprs {
+			tag, ok := splitTagString(t.staticValue(elem))
+			if !ok {
+				resolved = false
+				continue
+			}
+			tags = append(tags, tag)
+		}
+		return tags, resolved
+
+	// Otherwise, such as for expressions, evaluate the whole expression.
+	default:
+		return t.evaluateTags(expr)
+	}
+}
+
+// evaluateTags evaluates the expression and extracts the tags. It returns false if the
+// value is unknown, such as a variable without a default, or cannot be evaluated.
+func (t *tagResolver) evaluateTags(expr hcl.Expression) ([]resourceTag, bool) {
+	if !isStaticallyEvaluable(expr) {
+		return nil, false
+	}
+
+	var tags []resourceTag
+	resolved := false
+	if err := t.runner.EvaluateExpr(expr, func(val cty.Value) error {
+		// Unknown values, such as a variable without a default, may include any tags.
+		if !val.IsKnown() {
+			return nil
+		}
+		tags, resolved = getTags(val)
+		return nil
+	}, nil); err != nil {
+		return nil, false
+	}
+	return tags, resolved
+}
+
+// isStaticallyEvaluable returns whether the expression refers to nothing but variables.
+// Other references such as resources are unknown until apply, and local variables are
+// resolved from their expressions instead.
+func isStaticallyEvaluable(expr hcl.Expression) bool {
+	for _, traversal := range expr.Variables() {
+		if root := traversal.RootName(); root != "var" && root != "terraform" {
+			return false
+		}
+	}
+	return true
+}
+
+// traverseSearchLookupExpr searches the tags of lookup(map, key, default). If the key
+// is unknown, only the tags in all values of the map and the default are always set.
+func (t *tagResolver) traverseSearchLookupExpr(expr *hclsyntax.FunctionCallExpr) ([]resourceTag, bool) {
+	if len(expr.Args) < 2 {
+		return t.evaluateTags(expr)
+	}
+	items, ok := t.mapItemExprs(expr.Args[0])
+	if !ok {
+		return t.evaluateTags(expr)
+	}
+
+	keyVal := t.staticValue(expr.Args[1])
+	var key string
+	keyKnown := keyVal.IsWhollyKnown() && !keyVal.IsNull() && !keyVal.IsMarked() && keyVal.Type() == cty.String
+	if keyKnown {
+		key = keyVal.AsString()
+	}
+
+	var candidates []hcl.Expression
+	for _, item := range items {
+		if !keyKnown {
+			candidates = append(candidates, item.Value)
+			continue
+		}
+		if itemKey, diags := item.Key.Value(nil); !diags.HasErrors() && itemKey.Type() == cty.String && itemKey.IsKnown() && itemKey.AsString() == key {
+			candidates = []hcl.Expression{item.Value}
+			break
+		}
+	}
+	if len(expr.Args) > 2 && (!keyKnown || len(candidates) == 0) {
+		candidates = append(candidates, expr.Args[2])
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+
+	var candidateTags [][]resourceTag
+	resolved := true
+	for _, candidate := range candidates {
+		tags, candidateResolved := t.traverseSearchExpr(candidate)
+		candidateTags = append(candidateTags, tags)
+		resolved = resolved && candidateResolved
+	}
+	return intersectTags(candidateTags...), resolved
+}
+
+// intersectTags returns the tags set in all of the candidates, such as both branches of
+// a conditional. The value is kept only if it is the same in all of the candidates.
+func intersectTags(candidates ...[]resourceTag) []resourceTag {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// A tag set more than once takes the last value like merge().
+	lastTag := func(tags []resourceTag, key string) (resourceTag, bool) {
+		for i := len(tags) - 1; i >= 0; i-- {
+			if tags[i].key == key {
+				return tags[i], true
+			}
+		}
+		return resourceTag{}, false
+	}
+
+	var tags []resourceTag
+	seen := map[string]bool{}
+	for _, first := range candidates[0] {
+		if seen[first.key] {
+			continue
+		}
+		seen[first.key] = true
+
+		tag, _ := lastTag(candidates[0], first.key)
+		inAll := true
+		for _, candidate := range candidates[1:] {
+			other, exists := lastTag(candidate, tag.key)
+			if !exists {
+				inAll = false
+				break
+			}
+			if !tag.value.RawEquals(other.value) {
+				tag.value = cty.UnknownVal(cty.String)
+			}
+		}
+		if inAll {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Search the tags of an expression in JSON syntax. The keys of objects and lists
+// are extracted without evaluating the values, a string referring to a local variable
+// such as "${local.tags}" is traversed, and any other string is evaluated as a whole.
+func (t *tagResolver) traverseSearchJSONExpr(expr hcl.Expression) ([]resourceTag, bool) {
+	if tags, resolved, ok := t.getJSONTags(expr); ok {
+		return tags, resolved
+	}
+
+	if syntaxExpr, ok := nativeExpr(t.runner, expr); ok {
+		if traversal, ok := syntaxExpr.(*hclsyntax.ScopeTraversalExpr); ok && traversal.Traversal.RootName() == "local" {
+			return t.evaluateLocalVarTags(traversal.Traversal)
+		}
+	}
+
+	return t.evaluateTags(expr)
+}
+
+// Extract the tags from an object or a list in JSON syntax, return false if the
+// expression is neither an object nor a list. Only literal values are known, because
+// the values are not evaluated. The second result is false if some keys are unknown.
+func (t *tagResolver) getJSONTags(expr hcl.Expression) ([]resourceTag, bool, bool) {
+	var tags []resourceTag
+	resolved := true
+	if pairs, diags := hcl.ExprMap(expr); !diags.HasErrors() {
+		for _, pair := range pairs {
+			key, diags := pair.Key.Value(nil)
+			if diags.HasErrors() || key.Type() != cty.String || !key.IsKnown() {
+				resolved = false
+				continue
+			}
+			value := cty.UnknownVal(cty.String)
+			if literal, ok := literalValue(pair.Value); ok {
+				value = literal
+			}
+			tags = append(tags, resourceTag{key: key.AsString(), value: value})
+		}
+		return tags, resolved, true
+	}
+
+	// If tags is list value, used in Openstack provider like compute_instance_v2.
+	elems, diags := hcl.ExprList(expr)
+	if diags.HasErrors() {
+		return nil, false, false
+	}
+	for _, elem := range elems {
+		val := cty.UnknownVal(cty.String)
+		if syntaxExpr, ok := nativeExpr(t.runner, elem); ok {
+			val = literalTemplateValue(syntaxExpr)
+		}
+		tag, ok := splitTagString(val)
+		if !ok {
+			resolved = false
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags, resolved, true
+}
+
+// evaluateLocalVarTags searches the tags of a local variable, or an attribute of a
+// local variable such as `local.common.tags`. It returns false if the local variable
+// or the attribute cannot be found. The tags are memoized by the traversal.
+func (t *tagResolver) evaluateLocalVarTags(traversal hcl.Traversal) ([]resourceTag, bool) {
+	key, cacheable := traversalKey(traversal)
+	if cached, exists := t.cache[key]; cacheable && exists {
+		return cached.tags, cached.resolved
+	}
+
+	var tags []resourceTag
+	resolved := false
+	if expr, exists := t.resolveLocalExpr(traversal); exists {
+		// Because there might be function call like merge() and concat() in
+		// local variables, or even using another local variable, so it will
+		// requires to perform a deep traverse into the nested local variable.
+		tags, resolved = t.traverseSearchExpr(expr)
+	}
+
+	if cacheable {
+		t.cache[key] = resolvedTags{tags: tags, resolved: resolved}
+	}
+	return tags, resolved
+}
+
+// traversalKey returns the key of the traversal to memoize the tags, such as
+// `local.common["tags"]`. It returns false if the traversal has other steps than
+// attributes and indexes of known values.
+func traversalKey(traversal hcl.Traversal) (string, bool) {
+	var key strings.Builder
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			key.WriteString(step.Name)
+		case hcl.TraverseAttr:
+			fmt.Fprintf(&key, ".%s", step.Name)
+		case hcl.TraverseIndex:
+			if !step.Key.IsWhollyKnown() || step.Key.IsNull() {
+				return "", false
+			}
+			fmt.Fprintf(&key, "[%#v]", step.Key)
+		default:
+			return "", false
+		}
+	}
+	return key.String(), true
+}
+
+// resolveLocalExpr returns the expression of the local variable referred by the
+// traversal, following the attributes of object values and other local variables.
+// Local variables in a reference cycle cannot be resolved.
+func (t *tagResolver) resolveLocalExpr(traversal hcl.Traversal) (hcl.Expression, bool) {
+	if len(traversal) < 2 {
+		return nil, false
+	}
+	name, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok || t.cyclic[name.Name] {
+		return nil, false
+	}
+	attr, exists := t.locals[name.Name]
+	if !exists {
+		return nil, false
+	}
+	expr := attr.Expr
+
+	for i, step := range traversal[2:] {
+		// An attribute of a local variable referring to another local variable,
+		// such as `local.common.tags` where `common = local.base`.
+		if syntaxExpr, ok := nativeExpr(t.runner, expr); ok {
+			if ref, ok := syntaxExpr.(*hclsyntax.ScopeTraversalExpr); ok && ref.Traversal.RootName() == "local" {
+				return t.resolveLocalExpr(slices.Concat(ref.Traversal, traversal[2+i:]))
+			}
+		}
+
+		var key string
+		switch step := step.(type) {
+		case hcl.TraverseAttr:
+			key = step.Name
+		case hcl.TraverseIndex:
+			if step.Key.Type() != cty.String || !step.Key.IsKnown() {
+				return nil, false
+			}
+			key = step.Key.AsString()
+		default:
+			return nil, false
+		}
+
+		items, ok := t.mapItemExprs(expr)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for _, item := range items {
+			if itemKey, diags := item.Key.Value(nil); !diags.HasErrors() && itemKey.Type() == cty.String && itemKey.IsKnown() && itemKey.AsString() == key {
+				expr, found = item.Value, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return expr, true
+}
+
+// mapItemExprs returns the items of an object expression, or of the local variable
+// referring to an object expression. It returns false if the items cannot be found.
+func (t *tagResolver) mapItemExprs(expr hcl.Expression) ([]hcl.KeyValuePair, bool) {
+	if syntaxExpr, ok := nativeExpr(t.runner, expr); ok {
+		if traversal, ok := syntaxExpr.(*hclsyntax.ScopeTraversalExpr); ok && traversal.Traversal.RootName() == "local" {
+			localExpr, exists := t.resolveLocalExpr(traversal.Traversal)
+			if !exists {
+				return nil, false
+			}
+			expr = localExpr
+		}
+	}
+
+	items, diags := hcl.ExprMap(expr)
+	if diags.HasErrors() {
+		return nil, false
+	}
+	return items, true
+}
+
+// staticValue resolves the value of the expression statically, walking the syntax tree
+// so that the unresolved parts are unknown values rather than errors. Local variables
+// are resolved from their expressions, and other parts are evaluated on their own if
+// they are statically evaluable. A part which cannot be evaluated, such as an attribute
+// of a null value, is unknown as well.
+func (t *tagResolver) staticValue(expr hclsyntax.Expression) cty.Value {
+	switch expr := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return expr.Val
+
+	// A key of an object, which is a string if it is a bare identifier.
+	case *hclsyntax.ObjectConsKeyExpr:
+		if keyword := hcl.ExprAsKeyword(expr.Wrapped); keyword != "" && !expr.ForceNonLiteral {
+			return cty.StringVal(keyword)
+		}
+		return t.staticValue(expr.Wrapped)
+
+	case *hclsyntax.TemplateWrapExpr:
+		return t.staticValue(expr.Wrapped)
+
+	case *hclsyntax.ParenthesesExpr:
+		return t.staticValue(expr.Expression)
+
+	case *hclsyntax.TemplateExpr:
+		parts := make([]cty.Value, len(expr.Parts))
+		for i, part := range expr.Parts {
+			parts[i] = t.staticValue(part)
+		}
+		return templateValue(parts)
+
+	case *hclsyntax.ScopeTraversalExpr:
+		if expr.Traversal.RootName() == "local" {
+			localExpr, exists := t.resolveLocalExpr(expr.Traversal)
+			if !exists {
+				return cty.DynamicVal
+			}
+			// Expressions in JSON syntax are not evaluated, see literalValue.
+			if syntaxExpr, ok := localExpr.(hclsyntax.Expression); ok {
+				return t.staticValue(syntaxExpr)
+			}
+			if val, ok := literalValue(localExpr); ok {
+				return val
+			}
+			return cty.DynamicVal
+		}
+	}
+
+	if !isStaticallyEvaluable(expr) {
+		return cty.DynamicVal
+	}
+	val := cty.DynamicVal
+	if err := t.runner.EvaluateExpr(expr, func(v cty.Value) error {
+		val = v
+		return nil
+	}, nil); err != nil {
+		return cty.DynamicVal
+	}
+	return val
+}
+
+// getTags extracts the tags from an evaluated object or list value. It returns false if
+// the key of some tags in a list is unknown. The keys of sensitive tags are extracted,
+// but the values are unknown, so that they are neither printed in issues nor checked
+// against tag_values.
+func getTags(val cty.Value) ([]resourceTag, bool) {
+	val, valMarks := val.Unmark()
+	if !val.IsKnown() || val.IsNull() || !val.CanIterateElements() {
+		return []resourceTag{}, val.IsKnown()
+	}
+
+	var localTags []resourceTag
+	resolved := true
+	switch {
+	case val.Type().IsObjectType() || val.Type().IsMapType():
+		// If tags is object value
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			if len(valMarks) > 0 || v.ContainsMarked() {
+				v = cty.UnknownVal(v.Type())
+			}
+			localTags = append(localTags, resourceTag{key: k.AsString(), value: v})
+		}
+	case val.Type().IsTupleType() || val.Type().IsListType() || val.Type().IsSetType():
+		// If tags is list value, used in Openstack provider like compute_instance_v2.
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			tag, ok := splitTagString(v.WithMarks(valMarks))
+			if !ok {
+				resolved = false
+				continue
+			}
+			localTags = append(localTags, tag)
+		}
+	}
+	return localTags, resolved
+}
+
+// literalTemplateValue returns the value of a literal or a template without evaluating
+// any expression, which is used for expressions converted from JSON syntax.
+func literalTemplateValue(expr hclsyntax.Expression) cty.Value {
+	switch expr := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return expr.Val
+	case *hclsyntax.TemplateWrapExpr:
+		return literalTemplateValue(expr.Wrapped)
+	case *hclsyntax.TemplateExpr:
+		parts := make([]cty.Value, len(expr.Parts))
+		for i, part := range expr.Parts {
+			parts[i] = literalTemplateValue(part)
+		}
+		return templateValue(parts)
+	}
+	return cty.DynamicVal
+}
+
+// templateValue joins the values of the template parts. If a part is unknown or
+// sensitive, the result is an unknown string refined with the known prefix, such as
+// "env:" of "env:${var.env}".
+func templateValue(parts []cty.Value) cty.Value {
+	var prefix strings.Builder
+	for _, part := range parts {
+		str, err := convert.Convert(part, cty.String)
+		if err != nil || str.IsNull() {
+			return cty.UnknownVal(cty.String)
+		}
+		if !str.IsKnown() || str.IsMarked() {
+			return cty.UnknownVal(cty.String).Refine().StringPrefix(prefix.String()).NewValue()
+		}
+		prefix.WriteString(str.AsString())
+	}
+	return cty.StringVal(prefix.String())
+}
+
+// Split a single tag in string with delimiter ':' into the key and the value. It returns
+// false if the key is unknown, such as "${var.key}:value". The value of a sensitive tag
+// is unknown, so that it is not printed in issues.
+func splitTagString(val cty.Value) (resourceTag, bool) {
+	val, valMarks := val.Unmark()
+	if val.IsNull() || !val.Type().Equals(cty.String) {
+		return resourceTag{}, false
+	}
+	// If the value is unknown, AsString() will throw panic errors.
+	if !val.IsKnown() {
+		key, _, found := strings.Cut(val.Range().StringPrefix(), ":")
+		if !found {
+			return resourceTag{}, false
+		}
+		return resourceTag{key: key, value: cty.UnknownVal(cty.String), list: true}, true
+	}
+	key, value, found := strings.Cut(val.AsString(), ":")
+	switch {
+	case !found:
+		return resourceTag{key: key, value: cty.NullVal(cty.String), list: true}, true
+	case len(valMarks) > 0:
+		return resourceTag{key: key, value: cty.UnknownVal(cty.String), list: true}, true
+	}
+	return resourceTag{key: key, value: cty.StringVal(value), list: true}, true
+}
@@ -0,0 +1,43 @@
+package rules
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/myklst/tflint-ruleset-myklst/providerschema"
+)
+
+// taggableResourcesList is the resource types of AWS, Google Cloud, Alibaba Cloud and
+// Azure which support tags. It can be regenerated from the schemas of the provider
+// versions pinned in tools/taggable-resources/providers.tf by `make taggable-resources`.
+//
+//go:embed taggable_resources.txt
+var taggableResourcesList string
+
+var taggableResources = parseTaggableResources(taggableResourcesList)
+
+// parseTaggableResources parses the resource types, one per line. Empty lines and
+// lines starting with "#" are skipped.
+func parseTaggableResources(list string) map[string]bool {
+	resources := map[string]bool{}
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		resources[line] = true
+	}
+	return resources
+}
+
+// isTaggable returns whether the resource type supports the tag attribute. The
+// resource types in the providers schema file are checked against the schema, and
+// the others against the embedded list.
+func isTaggable(schemas *providerschema.Schemas, resourceType string, tagAttr *tagAttribute) bool {
+	if schemas != nil {
+		if block, exists := schemas.Resource(resourceType); exists {
+			return block.HasPath(tagAttr.path)
+		}
+	}
+	return taggableResources[resourceType]
+}
@@ -47,7 +47,7 @@ func (r *TerraformAnyTypeVariables) Link() string {
 func (r *TerraformAnyTypeVariables) Check(runner tflint.Runner) error {
 	config := &terraformAnyTypeVariablesConfig{}
 
-	if err := runner.DecodeRuleConfig(r.Name(), config); err != nil {
+	if err := decodeRuleConfig(runner, r, config); err != nil {
 		return err
 	}
 
@@ -81,7 +81,13 @@ func (r *TerraformAnyTypeVariables) Check(runner tflint.Runner) error {
 			continue
 		}
 
-		for _, typeExpr := range typeAttr.Expr.Variables() {
+		// Type constraints in JSON syntax are strings, which are parsed into native syntax.
+		syntaxExpr, ok := nativeTypeExpr(runner, typeAttr.Expr)
+		if !ok {
+			continue
+		}
+
+		for _, typeExpr := range syntaxExpr.Variables() {
 			if typeExpr.RootName() == "any" {
 				if err := runner.EmitIssue(r,
 					fmt.Sprintf("variable '%s' has 'any' type declared", variable.Labels[0]),
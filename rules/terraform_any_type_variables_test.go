@@ -113,3 +113,73 @@ rule "terraform_any_type_variables" {
   ignore_vars = ["my_ignored_var"]
 }
 `
+
+func Test_TerraformAnyTypeVariables_JSON(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Config   string
+		Expected helper.Issues
+	}{
+		{
+			Name: "simple variable without 'any' type",
+			Content: `{
+  "variable": {
+    "my_var": {
+      "type": "string"
+    }
+  }
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "ignored variable with 'any' type",
+			Content: `{
+  "variable": {
+    "my_ignored_var": {
+      "type": "any"
+    }
+  }
+}`,
+			Config:   testTerraformAnyTypeVariablesConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "complex variable with 'any' type",
+			Content: `{
+  "variable": {
+    "my_var": {
+      "type": "object({ my_key = any })"
+    }
+  }
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformAnyTypeVariables(),
+					Message: "variable 'my_var' has 'any' type declared",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 4, Column: 34},
+						End:      hcl.Pos{Line: 4, Column: 37},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformAnyTypeVariables()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf.json": test.Content,
+				".tflint.hcl":  test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
@@ -2,11 +2,14 @@ package rules
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/myklst/tflint-ruleset-myklst/project"
+	"github.com/terraform-linters/tflint-plugin-sdk/logger"
 	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
 )
 
@@ -15,6 +18,56 @@ type TerraformMetaArguments struct {
 	tflint.DefaultRule
 }
 
+type terraformMetaArgumentsConfig struct {
+	Order map[string][]string `hclext:"order,optional"`
+}
+
+// metaArgumentsOthers is the placeholder in `order` for all the other attributes
+// and blocks which are not listed in `order`.
+const metaArgumentsOthers = "..."
+
+// defaultMetaArgumentsOrder is the order of meta arguments and nested blocks of each
+// block type when it is not specified in `order`.
+var defaultMetaArgumentsOrder = map[string][]string{
+	"module":   {"source", "count", "for_each", "providers", metaArgumentsOthers},
+	"resource": {"count", "for_each", "provider", metaArgumentsOthers, "lifecycle"},
+	"data":     {"count", "for_each", "provider", metaArgumentsOthers, "lifecycle"},
+}
+
+// metaArgument is an attribute, or a group of nested blocks with the same type,
+// listed in `order`.
+type metaArgument struct {
+	name      string
+	rank      int
+	rng       hcl.Range
+	startLine int
+	endLine   int
+}
+
+// validate checks the block types and the duplicates in `order`. Block types which are
+// not specified in `order` use the default order.
+func (config *terraformMetaArgumentsConfig) validate() error {
+	order := make(map[string][]string, len(defaultMetaArgumentsOrder))
+	for _, blockType := range slices.Sorted(maps.Keys(config.Order)) {
+		if _, exists := defaultMetaArgumentsOrder[blockType]; !exists {
+			return validateOneOf("order", blockType, []string{"module", "resource", "data"})
+		}
+		for i, name := range config.Order[blockType] {
+			if slices.Contains(config.Order[blockType][:i], name) {
+				return configErrorf(fmt.Sprintf("order.%s[%d]", blockType, i), "`%s` is listed more than once", name)
+			}
+		}
+		order[blockType] = config.Order[blockType]
+	}
+	for blockType, defaultOrder := range defaultMetaArgumentsOrder {
+		if _, exists := order[blockType]; !exists {
+			order[blockType] = defaultOrder
+		}
+	}
+	config.Order = order
+	return nil
+}
+
 // NewTerraformMetaArguments returns a new rule
 func NewTerraformMetaArguments() *TerraformMetaArguments {
 	return &TerraformMetaArguments{}
@@ -42,12 +95,25 @@ func (r *TerraformMetaArguments) Link() string {
 
 // Check checks whether variables have type
 func (r *TerraformMetaArguments) Check(runner tflint.Runner) error {
+	config := &terraformMetaArgumentsConfig{}
+
+	if err := decodeRuleConfig(runner, r, config); err != nil {
+		return err
+	}
+	order := config.Order
+
 	files, err := runner.GetFiles()
 	if err != nil {
 		return err
 	}
 
-	for _, file := range files {
+	for filename, file := range files {
+		// The arrangement of meta arguments is a layout check, which does not apply to JSON syntax.
+		if isJSONSyntax(filename) {
+			logger.Debug(fmt.Sprintf("skip checking meta arguments arrangement in JSON syntax file '%s'", filename))
+			continue
+		}
+
 		fileContents, _, diags := file.Body.PartialContent(&hcl.BodySchema{
 			Blocks: []hcl.BlockHeaderSchema{
 				{
@@ -68,231 +134,94 @@ func (r *TerraformMetaArguments) Check(runner tflint.Runner) error {
 			return diags
 		}
 
-		for _, fileContent := range fileContents.Blocks {
-			var content *hcl.BodyContent
-			switch fileContent.Type {
-			case "module":
-				content, _, diags = fileContent.Body.PartialContent(&hcl.BodySchema{
-					Attributes: []hcl.AttributeSchema{
-						{Name: "source"},
-						{Name: "count"},
-						{Name: "for_each"},
-						{Name: "providers"},
-					},
-				})
-			case "resource", "data":
-				content, _, diags = fileContent.Body.PartialContent(&hcl.BodySchema{
-					Attributes: []hcl.AttributeSchema{
-						{Name: "count"},
-						{Name: "for_each"},
-						{Name: "provider"},
-					},
-					Blocks: []hcl.BlockHeaderSchema{
-						{
-							Type:       "lifecycle",
-							LabelNames: []string{},
-						},
-					},
-				})
-			}
-			if diags.HasErrors() {
-				return diags
-			}
+		// Lex the file once, tokens are looked up by line for all blocks in the file.
+		tokens := newLineTokens(file.Bytes, filename)
 
-			// currentLine is used as a pointer to perform checking.
-			// filename is used in function countCommentLine().
+		for _, fileContent := range fileContents.Blocks {
 			// fileContentBody is used to check resource block ending line.
-			var currentLine int
-			filename := fileContent.DefRange.Filename
 			fileContentBody := fileContent.Body.(*hclsyntax.Body)
-
-			// Move pointer 'currentLine' to next line after 'module' definition and ignore comment lines.
-			commentLines := r.countCommentLinesForward(file, filename, fileContent.DefRange.End.Line+1)
-			currentLine = fileContent.DefRange.End.Line + commentLines + 1
-
-			// Check meta argument 'source'.
-			source, sourceExist := content.Attributes["source"]
-			if sourceExist {
-				if currentLine != source.Range.Start.Line {
-					if err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("%s '%s' has invalid 'source' meta argument arrangement", fileContent.Type, strings.Join(fileContent.Labels, ".")),
-						source.Range,
-					); err != nil {
-						return err
-					}
-					continue
-				}
-				// Move pointer 'currentLine' to next line after 'source' meta argument and ignore comment lines.
-				commentLines := r.countCommentLinesForward(file, filename, source.Range.End.Line+1)
-				currentLine = source.Range.End.Line + commentLines + 1
-
-				// Check new line after meta argument 'source'.
-				// Ignore if next line is end of resource (there is no other attributes).
-				if currentLine != fileContentBody.EndRange.Start.Line && !r.isNewLine(file, filename, currentLine) {
-					if err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("%s '%s' has missing new line after 'source' meta argument", fileContent.Type, strings.Join(fileContent.Labels, ".")),
-						source.Range,
-					); err != nil {
-						return err
-					}
-					continue
-				}
-
-				// Move pointer 'currentLine' to next line after the new line and ignore comment lines.
-				commentLines = r.countCommentLinesForward(file, filename, currentLine+1)
-				currentLine = currentLine + commentLines + 1
+			blockName := strings.Join(fileContent.Labels, ".")
+
+			// Meta arguments listed before the placeholder are placed at the beginning of the
+			// block, and the ones listed after the placeholder are placed at the end of the block.
+			head, tail := r.splitOrder(order[fileContent.Type])
+
+			// fix rearranges the whole block body, so every issue in the same block shares it.
+			fix := r.fixMetaArguments(tokens, head, tail, fileContentBody)
+
+			// All the checks are performed for every meta argument, and the expected position of
+			// each meta argument is computed from where the previous one actually is, so that a
+			// misplaced meta argument does not cause issues on the following ones.
+			emit := func(arg metaArgument, format string) error {
+				return runner.EmitIssueWithFix(
+					r,
+					fmt.Sprintf("%s '%s' "+format, fileContent.Type, blockName, arg.name),
+					arg.rng,
+					fix,
+				)
 			}
 
-			// Check meta arguments 'count' or 'for_each'.
-			// Only one of 'count' and 'for_each' are allowed in Terraform meta argument.
-			count, countExist := content.Attributes["count"]
-			forEach, forEachExist := content.Attributes["for_each"]
-			if countExist {
-				if currentLine != count.Range.Start.Line {
-					if err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("%s '%s' has invalid 'count' meta argument arrangement", fileContent.Type, strings.Join(fileContent.Labels, ".")),
-						count.Range,
-					); err != nil {
+			// The first meta argument is expected at the next line after block definition,
+			// ignoring comment lines.
+			args := r.findMetaArguments(fileContentBody, head)
+			ordered := r.orderedMetaArguments(args)
+			currentLine := fileContent.DefRange.End.Line + 1
+			currentLine += tokens.countCommentLinesForward(currentLine)
+			for i, arg := range args {
+				// Check meta argument placement.
+				if !ordered[i] || currentLine != arg.startLine {
+					if err := emit(arg, "has invalid '%s' meta argument arrangement"); err != nil {
 						return err
 					}
-					continue
 				}
-				// Move pointer 'currentLine' to next line after 'count' meta argument and ignore comment lines.
-				commentLines := r.countCommentLinesForward(file, filename, count.Range.End.Line+1)
-				currentLine = count.Range.End.Line + commentLines + 1
 
-				// Check new line after meta argument 'count'.
-				// Ignore if next line is end of resource (there is no other attributes).
-				if currentLine != fileContentBody.EndRange.Start.Line && !r.isNewLine(file, filename, currentLine) {
-					if err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("%s '%s' has missing new line after 'count' meta argument", fileContent.Type, strings.Join(fileContent.Labels, ".")),
-						count.Range,
-					); err != nil {
-						return err
-					}
-					continue
-				}
+				// Move pointer 'currentLine' to next line after meta argument and ignore comment lines.
+				currentLine = arg.endLine + 1
+				currentLine += tokens.countCommentLinesForward(currentLine)
 
-				// Move pointer 'currentLine' to next line after the new line and ignore comment lines.
-				commentLines = r.countCommentLinesForward(file, filename, currentLine+1)
-				currentLine = currentLine + commentLines + 1
-			} else if forEachExist {
-				if currentLine != forEach.Range.Start.Line {
-					if err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("%s '%s' has invalid 'for_each' meta argument arrangement", fileContent.Type, fileContent.Labels[0]),
-						forEach.Range,
-					); err != nil {
-						return err
-					}
-					continue
-				}
-				// Move pointer 'currentLine' to next line after 'for_each' meta argument and ignore comment lines.
-				commentLines := r.countCommentLinesForward(file, filename, forEach.Range.End.Line+1)
-				currentLine = forEach.Range.End.Line + commentLines + 1
-
-				// Check new line after meta argument 'for_each'.
+				// Check new line after meta argument.
 				// Ignore if next line is end of resource (there is no other attributes).
-				if currentLine != fileContentBody.EndRange.Start.Line && !r.isNewLine(file, filename, currentLine) {
-					if err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("%s '%s' has missing new line after 'for_each' meta argument", fileContent.Type, strings.Join(fileContent.Labels, ".")),
-						forEach.Range,
-					); err != nil {
+				if currentLine != fileContentBody.EndRange.Start.Line && !tokens.isNewLine(currentLine) {
+					if err := emit(arg, "has missing new line after '%s' meta argument"); err != nil {
 						return err
 					}
 					continue
 				}
 
 				// Move pointer 'currentLine' to next line after the new line and ignore comment lines.
-				commentLines = r.countCommentLinesForward(file, filename, currentLine+1)
-				currentLine = currentLine + commentLines + 1
+				currentLine++
+				currentLine += tokens.countCommentLinesForward(currentLine)
 			}
 
-			// Check meta arguments 'providers' or 'provider'.
-			var providerExist bool
-			var provider *hcl.Attribute
-			var placementErrMsg, newLineErrMsg string
-
-			switch fileContent.Type {
-			case "module":
-				provider, providerExist = content.Attributes["providers"]
-				placementErrMsg = fmt.Sprintf("%s '%s' has invalid 'providers' meta argument arrangement",
-					fileContent.Type, strings.Join(fileContent.Labels, "."))
-				newLineErrMsg = fmt.Sprintf("%s '%s' has missing new line after 'providers' meta argument",
-					fileContent.Type, strings.Join(fileContent.Labels, "."))
-			case "resource", "data": // Combined case since they have identical handling
-				provider, providerExist = content.Attributes["provider"]
-				placementErrMsg = fmt.Sprintf("%s '%s' has invalid 'provider' meta argument arrangement",
-					fileContent.Type, strings.Join(fileContent.Labels, "."))
-				newLineErrMsg = fmt.Sprintf("%s '%s' has missing new line after 'provider' meta argument",
-					fileContent.Type, strings.Join(fileContent.Labels, "."))
-			}
-
-			if providerExist {
-				if currentLine != provider.Range.Start.Line {
-					if err := runner.EmitIssue(
-						r,
-						placementErrMsg,
-						provider.Range,
-					); err != nil {
+			// Check the meta arguments at the end of the block from the last one, the pointer
+			// 'currentLine' is moved to the line right after where the meta argument should end.
+			args = r.findMetaArguments(fileContentBody, tail)
+			ordered = r.orderedMetaArguments(args)
+			currentLine = fileContentBody.EndRange.Start.Line
+			for i := len(args) - 1; i >= 0; i-- {
+				arg := args[i]
+
+				// Check if newline exist one line before meta argument and ignore comment lines.
+				// Ignore if previous line is the block definition (there is no other attributes).
+				checkLine := arg.startLine - tokens.countCommentLinesBackward(arg.startLine-1) - 1
+				if checkLine != fileContent.DefRange.Start.Line && !tokens.isNewLine(checkLine) {
+					if err := emit(arg, "has missing new line before '%s' meta argument"); err != nil {
 						return err
 					}
-					continue
 				}
-				// Move pointer 'currentLine' to next line after 'provider' meta argument and ignore comment lines.
-				commentLines := r.countCommentLinesForward(file, filename, provider.Range.End.Line+1)
-				currentLine = provider.Range.End.Line + commentLines + 1
 
-				// Check new line after meta argument 'provider'.
-				// Ignore if next line is end of resource (there is no other attributes).
-				if currentLine != fileContentBody.EndRange.Start.Line && !r.isNewLine(file, filename, currentLine) {
-					if err := runner.EmitIssue(
-						r,
-						newLineErrMsg,
-						provider.Range,
-					); err != nil {
+				// Check if meta argument is placed at the expected line and ignore comment lines.
+				endLine := arg.endLine + tokens.countCommentLinesForward(arg.endLine+1)
+				if !ordered[i] || endLine+1 != currentLine {
+					if err := emit(arg, "has invalid '%s' meta argument arrangement"); err != nil {
 						return err
 					}
-					continue
 				}
-			}
 
-			// Check meta argument 'lifecycle'.
-			for _, contentBlock := range content.Blocks {
-				if contentBlock.Type == "lifecycle" {
-					// Check if newline exist one line before 'lifecycle' meta argument and ignore comment lines.
-					// Ignore if next line is end of resource (there is no other attributes).
-					commentLines := r.countCommentLinesBackward(file, filename, contentBlock.DefRange.Start.Line-1)
-					checkLine := contentBlock.DefRange.Start.Line - commentLines - 1
-					if checkLine != fileContent.DefRange.Start.Line && !r.isNewLine(file, filename, checkLine) {
-						if err := runner.EmitIssue(
-							r,
-							fmt.Sprintf("%s '%s' has missing new line before 'lifecycle' meta argument", fileContent.Type, strings.Join(fileContent.Labels, ".")),
-							contentBlock.DefRange,
-						); err != nil {
-							return err
-						}
-						continue
-					}
-
-					// Check if lifecycle is placed at the end of the module/resource/data source and ignore comment lines.
-					lifeCycleBlockBody := contentBlock.Body.(*hclsyntax.Body)
-					commentLines = r.countCommentLinesForward(file, filename, lifeCycleBlockBody.SrcRange.End.Line+1)
-					checkLine = lifeCycleBlockBody.SrcRange.End.Line + commentLines + 1
-					if checkLine != fileContentBody.EndRange.End.Line {
-						if err := runner.EmitIssue(
-							r,
-							fmt.Sprintf("%s '%s' has invalid 'lifecycle' meta argument arrangement", fileContent.Type, strings.Join(fileContent.Labels, ".")),
-							contentBlock.DefRange,
-						); err != nil {
-							return err
-						}
-					}
+				// Move pointer 'currentLine' to the new line before the meta argument.
+				currentLine = checkLine
+				if !tokens.isNewLine(checkLine) {
+					currentLine++
 				}
 			}
 		}
@@ -301,58 +230,114 @@ func (r *TerraformMetaArguments) Check(runner tflint.Runner) error {
 	return nil
 }
 
-func (r *TerraformMetaArguments) isNewLine(file *hcl.File, filename string, checkLine int) bool {
-	tokens, _ := hclsyntax.LexConfig(file.Bytes, filename, hcl.Pos{Line: 1, Column: 1})
+// splitOrder splits the order into meta arguments placed at the beginning and at the
+// end of the block. All meta arguments are placed at the beginning if there is no
+// placeholder in the order.
+func (r *TerraformMetaArguments) splitOrder(order []string) ([]string, []string) {
+	i := slices.Index(order, metaArgumentsOthers)
+	if i < 0 {
+		return order, nil
+	}
+	return order[:i], order[i+1:]
+}
+
+// findMetaArguments looks up the attributes with the given names, and the nested blocks
+// with the given types in the block body. The meta arguments are sorted by their
+// position in the block body.
+func (r *TerraformMetaArguments) findMetaArguments(body *hclsyntax.Body, names []string) []metaArgument {
+	var args []metaArgument
+	for rank, name := range names {
+		if attr, exists := body.Attributes[name]; exists {
+			args = append(args, metaArgument{
+				name:      name,
+				rank:      rank,
+				rng:       attr.SrcRange,
+				startLine: attr.SrcRange.Start.Line,
+				endLine:   attr.SrcRange.End.Line,
+			})
+			continue
+		}
 
-	for i, token := range tokens {
-		// Empty new lines are expected to have two TokenNewLine continuously.
-		if token.Range.End.Line == checkLine && token.Range.End.Column == 1 {
-			if i+1 <= len(tokens) && token.Type == hclsyntax.TokenNewline && tokens[i+1].Type == hclsyntax.TokenNewline {
-				return true
-			} else {
-				return false
+		var exists bool
+		for _, block := range body.Blocks {
+			if block.Type != name {
+				continue
+			}
+			if !exists {
+				args = append(args, metaArgument{
+					name:      name,
+					rank:      rank,
+					rng:       block.DefRange(),
+					startLine: block.Range().Start.Line,
+				})
+				exists = true
 			}
+			args[len(args)-1].endLine = block.Range().End.Line
 		}
 	}
-	return false
+
+	slices.SortFunc(args, func(a, b metaArgument) int { return a.startLine - b.startLine })
+	return args
 }
 
-func (r *TerraformMetaArguments) countCommentLinesForward(file *hcl.File, filename string, startingLine int) int {
-	var commentLinesCount int
-	tokens, _ := hclsyntax.LexConfig(file.Bytes, filename, hcl.Pos{Line: 1, Column: 1})
-
-checking:
-	for {
-		for _, token := range tokens {
-			if token.Range.Start.Line == startingLine+commentLinesCount {
-				if token.Type == hclsyntax.TokenComment {
-					commentLinesCount++
-					continue checking
-				} else {
-					return commentLinesCount
-				}
-			}
+// orderedMetaArguments marks the meta arguments which are in the configured order, the
+// meta arguments out of the order are the misplaced ones.
+func (r *TerraformMetaArguments) orderedMetaArguments(args []metaArgument) []bool {
+	ranks := make([]int, len(args))
+	for i, arg := range args {
+		ranks[i] = arg.rank
+	}
+	return orderedRanks(ranks)
+}
+
+// fixMetaArguments returns a fix function which rewrites the block body to place the
+// head meta arguments at the top of the block in the expected order, each followed by
+// a new line, and the tail meta arguments at the end of the block, each after a new line.
+// Other attributes and blocks keep their original order and spacing.
+func (r *TerraformMetaArguments) fixMetaArguments(tokens *lineTokens, head []string, tail []string, body *hclsyntax.Body) func(tflint.Fixer) error {
+	return func(f tflint.Fixer) error {
+		rng, text, ok := r.rearrangeMetaArguments(tokens, head, tail, body)
+		if !ok {
+			return tflint.ErrFixNotSupported
 		}
-		return commentLinesCount
+		return f.ReplaceText(rng, text)
 	}
 }
 
-func (r *TerraformMetaArguments) countCommentLinesBackward(file *hcl.File, filename string, startingLine int) int {
-	var commentLinesCount int
-	tokens, _ := hclsyntax.LexConfig(file.Bytes, filename, hcl.Pos{Line: 1, Column: 1})
-
-checking:
-	for {
-		for _, token := range tokens {
-			if token.Range.Start.Line == startingLine-commentLinesCount {
-				if token.Type == hclsyntax.TokenComment {
-					commentLinesCount++
-					continue checking
-				} else {
-					return commentLinesCount
-				}
+func (r *TerraformMetaArguments) rearrangeMetaArguments(tokens *lineTokens, head []string, tail []string, body *hclsyntax.Body) (hcl.Range, string, bool) {
+	items, ok := tokens.bodySegments(body)
+	if !ok {
+		return hcl.Range{}, "", false
+	}
+
+	var groups []string
+	for _, name := range head {
+		for _, item := range items {
+			if item.name == name {
+				groups = append(groups, tokens.segmentText(item))
+			}
+		}
+	}
+	var others string
+	for _, item := range items {
+		if slices.Contains(head, item.name) || slices.Contains(tail, item.name) {
+			continue
+		}
+		if others != "" && item.blankBefore {
+			others += "\n"
+		}
+		others += tokens.segmentText(item)
+	}
+	if others != "" {
+		groups = append(groups, others)
+	}
+	for _, name := range tail {
+		for _, item := range items {
+			if item.name == name {
+				groups = append(groups, tokens.segmentText(item))
 			}
 		}
-		return commentLinesCount
 	}
+
+	return tokens.bodyLinesRange(body), strings.Join(groups, "\n"), true
 }
@@ -1,17 +1,23 @@
 package rules
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/terraform-linters/tflint-plugin-sdk/helper"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
 )
 
 func Test_TerraformMetaArguments(t *testing.T) {
 	tests := []struct {
 		Name     string
 		Content  string
+		Config   string
 		Expected helper.Issues
+		Fixed    string
 	}{
 		{
 			Name: "source only in module",
@@ -364,6 +370,12 @@ module "my_module" {
 					},
 				},
 			},
+			Fixed: `
+module "my_module" {
+  source = "./my-module/"
+
+  name = "my name"
+}`,
 		},
 		{
 			Name: "source, count and attributes in module, invalid arrangement",
@@ -386,6 +398,14 @@ module "my_module" {
 					},
 				},
 			},
+			Fixed: `
+module "my_module" {
+  source = "./my-module/"
+
+  count = 3
+
+  name = "my name"
+}`,
 		},
 		{
 			Name: "count, provider and attributes in resource, invalid arrangement",
@@ -408,6 +428,14 @@ resource "foo" "my_resource" {
 					},
 				},
 			},
+			Fixed: `
+resource "foo" "my_resource" {
+  count = 3
+
+  provider = foo.default
+
+  name = "my name"
+}`,
 		},
 		{
 			Name: "count, provider, lifecycle and attributes in resource, invalid arrangement",
@@ -432,6 +460,16 @@ resource "foo" "my_resource" {
 					},
 				},
 			},
+			Fixed: `
+resource "foo" "my_resource" {
+  count = 3
+
+  provider = foo.default
+
+  name = "my name"
+
+  lifecycle {}
+}`,
 		},
 		{
 			Name: "source, count, providers and attributes in module, invalid arrangement",
@@ -456,6 +494,16 @@ module "my_module" {
 					},
 				},
 			},
+			Fixed: `
+module "my_module" {
+  source = "./my-module/"
+
+  count = 3
+
+  providers = {}
+
+  name = "my name"
+}`,
 		},
 		{
 			Name: "source and attributes in module, missing new line",
@@ -475,6 +523,12 @@ module "my_module" {
 					},
 				},
 			},
+			Fixed: `
+module "my_module" {
+  source = "./my-module/"
+
+  name = "my name"
+}`,
 		},
 		{
 			Name: "source, count and attributes in module, missing new line",
@@ -496,6 +550,14 @@ module "my_module" {
 					},
 				},
 			},
+			Fixed: `
+module "my_module" {
+  source = "./my-module/"
+
+  count = 3
+
+  name = "my name"
+}`,
 		},
 		{
 			Name: "source, count, providers and attributes in module, missing new line",
@@ -519,6 +581,16 @@ module "my_module" {
 					},
 				},
 			},
+			Fixed: `
+module "my_module" {
+  source = "./my-module/"
+
+  count = 3
+
+  providers = {}
+
+  name = "my name"
+}`,
 		},
 		{
 			Name: "lifecycle and attributes in resource, missing new line",
@@ -538,6 +610,12 @@ resource "foo" "my_resource" {
 					},
 				},
 			},
+			Fixed: `
+resource "foo" "my_resource" {
+  name = "my name"
+
+  lifecycle {}
+}`,
 		},
 		{
 			Name: "lifecycle and attributes in resource with comment, missing new line",
@@ -558,19 +636,505 @@ resource "foo" "my_resource" {
 					},
 				},
 			},
+			Fixed: `
+resource "foo" "my_resource" {
+  name = "my name"
+
+  # I'm a comment.
+  lifecycle {}
+}`,
+		},
+		{
+			Name: "meta arguments with comments in resource, invalid arrangement",
+			Content: `
+resource "foo" "my_resource" {
+  name = "my name"
+
+  # I'm a floating comment.
+
+  # I'm a provider comment.
+  provider = foo.default
+  # I'm a count comment.
+  count = 3
+  lifecycle {}
+
+  tags = {}
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has invalid 'provider' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 8, Column: 3},
+						End:      hcl.Pos{Line: 8, Column: 25},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has missing new line after 'provider' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 8, Column: 3},
+						End:      hcl.Pos{Line: 8, Column: 25},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has invalid 'count' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 10, Column: 3},
+						End:      hcl.Pos{Line: 10, Column: 12},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has missing new line after 'count' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 10, Column: 3},
+						End:      hcl.Pos{Line: 10, Column: 12},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has missing new line before 'lifecycle' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 11, Column: 3},
+						End:      hcl.Pos{Line: 11, Column: 12},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has invalid 'lifecycle' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 11, Column: 3},
+						End:      hcl.Pos{Line: 11, Column: 12},
+					},
+				},
+			},
+			Fixed: `
+resource "foo" "my_resource" {
+  # I'm a count comment.
+  count = 3
+
+  # I'm a provider comment.
+  provider = foo.default
+
+  name = "my name"
+
+  # I'm a floating comment.
+
+  tags = {}
+
+  lifecycle {}
+}`,
+		},
+		{
+			Name: "source, count, providers and attributes in module, multiple missing new lines and invalid arrangement",
+			Content: `
+module "my_module" {
+  count = 3
+  source = "./my-module/"
+  providers = {}
+  name = "my name"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "module 'my_module' has missing new line after 'count' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 3, Column: 12},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "module 'my_module' has invalid 'source' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 3},
+						End:      hcl.Pos{Line: 4, Column: 26},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "module 'my_module' has missing new line after 'source' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 3},
+						End:      hcl.Pos{Line: 4, Column: 26},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "module 'my_module' has missing new line after 'providers' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3},
+						End:      hcl.Pos{Line: 5, Column: 17},
+					},
+				},
+			},
+			Fixed: `
+module "my_module" {
+  source = "./my-module/"
+
+  count = 3
+
+  providers = {}
+
+  name = "my name"
+}`,
+		},
+		{
+			Name: "count, provider, lifecycle and attributes in resource, invalid arrangement and missing new line",
+			Content: `
+resource "foo" "my_resource" {
+  provider = foo.default
+
+  count = 3
+
+  name = "my name"
+  lifecycle {}
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has invalid 'count' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3},
+						End:      hcl.Pos{Line: 5, Column: 12},
+					},
+				},
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has missing new line before 'lifecycle' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 8, Column: 3},
+						End:      hcl.Pos{Line: 8, Column: 12},
+					},
+				},
+			},
+			Fixed: `
+resource "foo" "my_resource" {
+  count = 3
+
+  provider = foo.default
+
+  name = "my name"
+
+  lifecycle {}
+}`,
+		},
+		{
+			Name: "count, provider and attributes in resource, misplaced provider does not affect other meta arguments",
+			Content: `
+resource "foo" "my_resource" {
+  count = 3
+
+  name = "my name"
+
+  provider = foo.default
+
+  tags = {}
+
+  lifecycle {}
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has invalid 'provider' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 7, Column: 3},
+						End:      hcl.Pos{Line: 7, Column: 25},
+					},
+				},
+			},
+			Fixed: `
+resource "foo" "my_resource" {
+  count = 3
+
+  provider = foo.default
+
+  name = "my name"
+
+  tags = {}
+
+  lifecycle {}
+}`,
+		},
+		{
+			Name: "provider, count, depends_on, lifecycle and attributes in resource with custom order",
+			Content: `
+resource "foo" "my_resource" {
+  provider = foo.default
+
+  count = 3
+
+  name = "my name"
+
+  depends_on = []
+
+  lifecycle {}
+}`,
+			Config:   testTerraformMetaArgumentsCustomOrderConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "source, count and attributes in module with custom order of other block types",
+			Content: `
+module "my_module" {
+  source = "./my-module/"
+
+  count = 3
+
+  name = "my name"
+}`,
+			Config:   testTerraformMetaArgumentsCustomOrderConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "provider, count, depends_on, lifecycle and attributes in resource with custom order, invalid arrangement",
+			Content: `
+resource "foo" "my_resource" {
+  count = 3
+
+  provider = foo.default
+
+  name = "my name"
+
+  lifecycle {}
+}`,
+			Config: testTerraformMetaArgumentsCustomOrderConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has invalid 'provider' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3},
+						End:      hcl.Pos{Line: 5, Column: 25},
+					},
+				},
+			},
+			Fixed: `
+resource "foo" "my_resource" {
+  provider = foo.default
+
+  count = 3
+
+  name = "my name"
+
+  lifecycle {}
+}`,
+		},
+		{
+			Name: "depends_on, lifecycle and attributes in resource with custom order, invalid arrangement",
+			Content: `
+resource "foo" "my_resource" {
+  depends_on = []
+
+  name = "my name"
+
+  lifecycle {}
+}`,
+			Config: testTerraformMetaArgumentsCustomOrderConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has invalid 'depends_on' meta argument arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 3, Column: 18},
+					},
+				},
+			},
+			Fixed: `
+resource "foo" "my_resource" {
+  name = "my name"
+
+  depends_on = []
+
+  lifecycle {}
+}`,
+		},
+		{
+			Name: "depends_on, lifecycle and attributes in resource with custom order, missing new line",
+			Content: `
+resource "foo" "my_resource" {
+  name = "my name"
+
+  depends_on = []
+  lifecycle {}
+}`,
+			Config: testTerraformMetaArgumentsCustomOrderConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformMetaArguments(),
+					Message: "resource 'foo.my_resource' has missing new line before 'lifecycle' meta argument",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 6, Column: 3},
+						End:      hcl.Pos{Line: 6, Column: 12},
+					},
+				},
+			},
+			Fixed: `
+resource "foo" "my_resource" {
+  name = "my name"
+
+  depends_on = []
+
+  lifecycle {}
+}`,
 		},
 	}
 
 	rule := NewTerraformMetaArguments()
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			runner := helper.TestRunner(t, map[string]string{"main.tf": test.Content})
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf":     test.Content,
+				".tflint.hcl": test.Config,
+			})
 
 			if err := rule.Check(runner); err != nil {
 				t.Fatalf("Unexpected error occurred: %s", err)
 			}
 
 			helper.AssertIssues(t, test.Expected, runner.Issues)
+
+			want := map[string]string{}
+			if test.Fixed != "" {
+				want["main.tf"] = test.Fixed
+			}
+			helper.AssertChanges(t, want, runner.Changes())
+		})
+	}
+}
+
+func Test_TerraformMetaArguments_JSON(t *testing.T) {
+	runner := helper.TestRunner(t, map[string]string{
+		"main.tf.json": `{
+  "module": {
+    "my_module": {
+      "name": "my_name",
+      "count": 3,
+      "source": "./test"
+    }
+  },
+  "resource": {
+    "my_resource": {
+      "my_resource_name": {
+        "lifecycle": {
+          "create_before_destroy": true
+        },
+        "provider": "aws.west",
+        "count": 3
+      }
+    }
+  }
+}`,
+	})
+
+	if err := NewTerraformMetaArguments().Check(runner); err != nil {
+		t.Fatalf("Unexpected error occurred: %s", err)
+	}
+
+	helper.AssertIssues(t, helper.Issues{}, runner.Issues)
+	helper.AssertChanges(t, map[string]string{}, runner.Changes())
+}
+
+func Benchmark_TerraformMetaArguments(b *testing.B) {
+	for _, blocks := range []int{100, 300, 1000} {
+		content := syntheticTerraformMetaArgumentsModule(blocks)
+
+		b.Run(fmt.Sprintf("%d lines", strings.Count(content, "\n")), func(b *testing.B) {
+			file, diags := hclsyntax.ParseConfig([]byte(content), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				b.Fatalf("Unexpected parse error occurred: %s", diags)
+			}
+			runner := &benchmarkRunner{files: map[string]*hcl.File{"main.tf": file}}
+			rule := NewTerraformMetaArguments()
+
+			for b.Loop() {
+				if err := rule.Check(runner); err != nil {
+					b.Fatalf("Unexpected error occurred: %s", err)
+				}
+			}
 		})
 	}
 }
+
+// benchmarkRunner is a runner over the parsed files for benchmarks, as helper.TestRunner
+// requires a *testing.T. The fixes are computed but not applied, as the fixer of the
+// SDK is not a part of the rule.
+type benchmarkRunner struct {
+	tflint.Runner
+	files map[string]*hcl.File
+}
+
+func (r *benchmarkRunner) GetFiles() (map[string]*hcl.File, error) {
+	return r.files, nil
+}
+
+func (r *benchmarkRunner) DecodeRuleConfig(string, any) error {
+	return nil
+}
+
+func (r *benchmarkRunner) EmitIssueWithFix(_ tflint.Rule, _ string, _ hcl.Range, fixFunc func(f tflint.Fixer) error) error {
+	return fixFunc(benchmarkFixer{})
+}
+
+type benchmarkFixer struct {
+	tflint.Fixer
+}
+
+func (benchmarkFixer) ReplaceText(hcl.Range, ...any) error {
+	return nil
+}
+
+// syntheticTerraformMetaArgumentsModule generates a large module, every tenth block
+// has a missing new line so that the issues and fixes are also measured.
+func syntheticTerraformMetaArgumentsModule(blocks int) string {
+	var sb strings.Builder
+	for i := range blocks {
+		separator := "\n"
+		if i%10 == 0 {
+			separator = ""
+		}
+		fmt.Fprintf(&sb, `# Resource number %[1]d.
+resource "foo" "my_resource_%[1]d" {
+  # I'm a comment.
+  count = 3
+%[2]s  provider = foo.default
+
+  name = "my name %[1]d"
+  tags = {}
+
+  lifecycle {}
+}
+
+`, i, separator)
+	}
+	return sb.String()
+}
+
+const testTerraformMetaArgumentsCustomOrderConfig = `
+rule "terraform_meta_arguments" {
+  enabled = true
+
+  order = {
+    resource = ["provider", "count", "for_each", "...", "depends_on", "lifecycle"]
+  }
+}
+`
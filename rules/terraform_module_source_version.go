@@ -3,12 +3,16 @@ package rules
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/myklst/tflint-ruleset-myklst/project"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
@@ -24,7 +28,119 @@ func NewTerraformModuleSourceVersion() *TerraformModuleSourceVersion {
 }
 
 type TerraformModuleSourceVersionConfig struct {
-	AllowedVersions []string `hclext:"allowed_versions,optional"`
+	AllowedVersions         []string                             `hclext:"allowed_versions,optional"`
+	AllowedVersionOperators []string                             `hclext:"allowed_version_operators,optional"`
+	GitMirrorDir            string                               `hclext:"git_mirror_dir,optional"`
+	AllowedHosts            []string                             `hclext:"allowed_hosts,optional"`
+	DeniedHosts             []string                             `hclext:"denied_hosts,optional"`
+	AllowedProtocols        []string                             `hclext:"allowed_protocols,optional"`
+	PinStyle                string                               `hclext:"pin_style,optional"`
+	AllowPrerelease         *bool                                `hclext:"allow_prerelease,optional"`
+	PrereleasePaths         []string                             `hclext:"prerelease_paths,optional"`
+	PrereleaseEnvs          []string                             `hclext:"prerelease_envs,optional"`
+	AllowBuildMetadata      *bool                                `hclext:"allow_build_metadata,optional"`
+	VersionPolicies         []terraformModuleVersionPolicyConfig `hclext:"version_policy,block"`
+
+	allowedVersions []*regexp.Regexp
+	policies        []*moduleVersionPolicy
+}
+
+// registrySourcePattern matches module registry addresses in the form of
+// [<HOSTNAME>/]<NAMESPACE>/<NAME>/<PROVIDER>, with an optional //<SUBDIR>.
+var registrySourcePattern = regexp.MustCompile(`^(?:([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)+(?::\d+)?)/)?[0-9A-Za-z](?:[0-9A-Za-z_-]{0,62}[0-9A-Za-z])?/[0-9A-Za-z](?:[0-9A-Za-z_-]{0,62}[0-9A-Za-z])?/[0-9a-z]{1,64}(?://.*)?$`)
+
+// archiveVersionPattern matches a semantic version in a path segment of archive sources,
+// such as v1.4.0 or vpc-1.4.0.
+var archiveVersionPattern = regexp.MustCompile(`(?:^|[^0-9A-Za-z.])v?\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// archiveLatestPattern matches a path segment of archive sources referring to the latest version.
+var archiveLatestPattern = regexp.MustCompile(`(?i)(?:^|[-_.])latest(?:$|[-_.])`)
+
+// archiveExtensions are the archive formats supported by module sources, the longer
+// extensions are listed first.
+var archiveExtensions = []string{".tar.bz2", ".tar.gz", ".tar.xz", ".tbz2", ".tgz", ".txz", ".tar", ".zip", ".bz2", ".gz", ".xz"}
+
+// fullCommitPattern matches full commit SHAs of git (SHA-1 and SHA-256) and Mercurial.
+var fullCommitPattern = regexp.MustCompile(`^(?:[0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// shortCommitPattern matches abbreviated commit SHAs. Refs of decimal digits only are
+// considered versions rather than SHAs.
+var shortCommitPattern = regexp.MustCompile(`^[0-9a-f]*[a-f][0-9a-f]*$`)
+
+// versionOperators are the operators of version constraints supported by Terraform.
+var versionOperators = []string{"=", "!=", ">=", "<=", ">", "<", "~>"}
+
+// pinStyles are the supported values of pin_style.
+var pinStyles = []string{"tag", "sha", "tag_or_full_sha"}
+
+// revisionKind is the kind of the revision pinned by version control sources.
+type revisionKind int
+
+const (
+	revisionTag revisionKind = iota
+	revisionFullSHA
+	revisionShortSHA
+)
+
+// versionConstraintPattern splits a version constraint into the operator and the version.
+var versionConstraintPattern = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~>)?\s*(.+)$`)
+
+// validate sets the defaults of the config, and compiles the patterns and version policies.
+func (config *TerraformModuleSourceVersionConfig) validate() error {
+	// Only exact versions are allowed by default.
+	if len(config.AllowedVersionOperators) == 0 {
+		config.AllowedVersionOperators = []string{"="}
+	}
+	for i, operator := range config.AllowedVersionOperators {
+		if err := validateOneOf(fmt.Sprintf("allowed_version_operators[%d]", i), operator, versionOperators); err != nil {
+			return err
+		}
+	}
+
+	// Commit SHAs are only allowed when opted in, or matching allowed_versions.
+	if config.PinStyle == "" {
+		config.PinStyle = "tag"
+	}
+	if err := validateOneOf("pin_style", config.PinStyle, pinStyles); err != nil {
+		return err
+	}
+
+	// Prereleases are allowed by default, and only in modules with env = "dev" otherwise.
+	if config.AllowPrerelease == nil {
+		allowPrerelease := true
+		config.AllowPrerelease = &allowPrerelease
+	}
+	if len(config.PrereleaseEnvs) == 0 {
+		config.PrereleaseEnvs = []string{"dev"}
+	}
+	// Build metadata is allowed by default, as it does not change the version.
+	if config.AllowBuildMetadata == nil {
+		allowBuildMetadata := true
+		config.AllowBuildMetadata = &allowBuildMetadata
+	}
+
+	if err := validateGlobs("prerelease_paths", config.PrereleasePaths); err != nil {
+		return err
+	}
+	if err := validateGlobs("allowed_hosts", config.AllowedHosts); err != nil {
+		return err
+	}
+	if err := validateGlobs("denied_hosts", config.DeniedHosts); err != nil {
+		return err
+	}
+
+	if config.GitMirrorDir != "" {
+		if info, err := os.Stat(config.GitMirrorDir); err != nil || !info.IsDir() {
+			return configErrorf("git_mirror_dir", "`%s` is not a directory", config.GitMirrorDir)
+		}
+	}
+
+	var err error
+	if config.allowedVersions, err = compileRegexps("allowed_versions", config.AllowedVersions); err != nil {
+		return err
+	}
+	config.policies, err = newModuleVersionPolicies(config.VersionPolicies)
+	return err
 }
 
 // Name returns the rule name
@@ -51,9 +167,16 @@ func (r *TerraformModuleSourceVersion) Link() string {
 func (r *TerraformModuleSourceVersion) Check(runner tflint.Runner) error {
 	config := &TerraformModuleSourceVersionConfig{}
 
-	if err := runner.DecodeRuleConfig(r.Name(), config); err != nil {
+	if err := decodeRuleConfig(runner, r, config); err != nil {
 		return err
 	}
+	allowedVersions, policies := config.allowedVersions, config.policies
+
+	// Refs of git sources are verified against the local mirror if specified.
+	var mirror *gitMirror
+	if config.GitMirrorDir != "" {
+		mirror = newGitMirror(config.GitMirrorDir)
+	}
 
 	modules, err := runner.GetModuleContent(&hclext.BodySchema{
 		Blocks: []hclext.BlockSchema{
@@ -65,6 +188,12 @@ func (r *TerraformModuleSourceVersion) Check(runner tflint.Runner) error {
 						{
 							Name: "source",
 						},
+						{
+							Name: "version",
+						},
+						{
+							Name: "env",
+						},
 					},
 				},
 			},
@@ -85,16 +214,23 @@ func (r *TerraformModuleSourceVersion) Check(runner tflint.Runner) error {
 			return err
 		}
 
-		source, err := getter.Detect(sourceValue, filepath.Dir(module.DefRange.Filename), []getter.Detector{
-			new(getter.GitHubDetector),
-			new(getter.GitDetector),
-			new(getter.FileDetector),
-		})
+		// Registry sources are pinned by the version attribute instead of the URL.
+		if r.isRegistrySource(sourceValue) {
+			if err := r.checkSourceLocation(runner, module, sourceValue, r.registryHost(sourceValue), "", config); err != nil {
+				return err
+			}
+			if err := r.checkRegistrySource(runner, module, sourceValue, config.AllowedVersionOperators, allowedVersions, policies, config); err != nil {
+				return err
+			}
+			continue
+		}
+
+		source, err := getter.Detect(sourceValue, filepath.Dir(module.DefRange.Filename), moduleSourceDetectors)
 		if err != nil {
 			return err
 		}
 
-		u, err := url.ParseRequestURI(source)
+		scheme, u, err := parseModuleSourceURL(source)
 		if err != nil {
 			if _err := runner.EmitIssue(
 				r,
@@ -106,64 +242,489 @@ func (r *TerraformModuleSourceVersion) Check(runner tflint.Runner) error {
 			continue
 		}
 
-		// Only enforce version checks for git-based sources
-		if u.Scheme != "git" {
-			continue
+		// Local paths are not restricted by hosts and protocols.
+		if scheme != "file" {
+			// The protocol is the forced getter with the scheme of the URL, such as git::https.
+			protocol := u.Scheme
+			if scheme != u.Scheme {
+				protocol = scheme + "::" + u.Scheme
+			}
+			if err := r.checkSourceLocation(runner, module, sourceValue, u.Hostname(), protocol, config); err != nil {
+				return err
+			}
 		}
 
-		if u.Opaque != "" {
-			query := u.RawQuery
-			u, err = url.Parse(strings.TrimPrefix(u.Opaque, ":"))
-			if err != nil {
-				return err
+		// Only enforce version checks for remote sources, each getter is pinned differently.
+		switch scheme {
+		case "git":
+			err = r.checkRevisionSource(runner, module, sourceValue, u, []string{"ref", "rev"}, allowedVersions, config.PinStyle)
+			if err == nil && mirror != nil {
+				err = r.checkGitMirror(runner, module, sourceValue, u, mirror)
 			}
-			u.RawQuery = query
+			if err == nil {
+				err = r.checkRevisionPolicy(runner, module, sourceValue, u, []string{"ref", "rev"}, policies, mirror, config)
+			}
+		case "hg":
+			err = r.checkRevisionSource(runner, module, sourceValue, u, []string{"rev"}, allowedVersions, config.PinStyle)
+			if err == nil {
+				err = r.checkRevisionPolicy(runner, module, sourceValue, u, []string{"rev"}, policies, nil, config)
+			}
+		case "s3", "gcs", "http", "https":
+			err = r.checkArchiveSource(runner, module, sourceValue, u, allowedVersions)
+		}
+		if err != nil {
+			return err
 		}
+	}
 
-		query := u.Query()
+	return nil
+}
 
-		revision := query.Get("ref")
-		key := "ref"
+// checkSourceLocation checks whether the host and the protocol of the module source are
+// allowed. Registry sources have no protocol, which is not checked.
+func (r *TerraformModuleSourceVersion) checkSourceLocation(runner tflint.Runner, module *hclext.Block, source string, host string, protocol string, config *TerraformModuleSourceVersionConfig) error {
+	sourceAttr := module.Body.Attributes["source"]
+	host = strings.ToLower(host)
 
-		if revision == "" {
-			revision = query.Get("rev")
-			key = "rev"
+	var messages []string
+	if len(config.AllowedHosts) > 0 && !r.matchHost(host, config.AllowedHosts) {
+		messages = append(messages, fmt.Sprintf("module '%s' source '%s' uses host '%s', which is not in allowed_hosts", module.Labels[0], source, host))
+	}
+	if r.matchHost(host, config.DeniedHosts) {
+		messages = append(messages, fmt.Sprintf("module '%s' source '%s' uses host '%s', which is in denied_hosts", module.Labels[0], source, host))
+	}
+	if protocol != "" && len(config.AllowedProtocols) > 0 && !slices.Contains(config.AllowedProtocols, protocol) {
+		messages = append(messages, fmt.Sprintf("module '%s' source '%s' uses protocol '%s', which is not in allowed_protocols", module.Labels[0], source, protocol))
+	}
 
-			if revision == "" {
-				if _err := runner.EmitIssue(
-					r,
-					fmt.Sprintf(`module '%s' source '%s' is not pinned (missing ?ref= or ?rev= in the URL).`, module.Labels[0], sourceValue),
-					sourceAttr.Expr.Range(),
-				); _err != nil {
-					return _err
-				}
+	for _, message := range messages {
+		if err := runner.EmitIssue(r, message, sourceAttr.Expr.Range()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchHost returns whether the host matches any of the patterns, such as *.example.com.
+func (r *TerraformModuleSourceVersion) matchHost(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), host); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// registryHost returns the hostname of the registry source, which is the public Terraform
+// registry if the hostname is omitted.
+func (r *TerraformModuleSourceVersion) registryHost(source string) string {
+	if matches := registrySourcePattern.FindStringSubmatch(source); matches != nil && matches[1] != "" {
+		host, _, _ := strings.Cut(matches[1], ":")
+		return host
+	}
+	return "registry.terraform.io"
+}
+
+// isRegistrySource returns whether the source is a module registry address. Sources on
+// github.com and bitbucket.org in the same form are not registry sources.
+func (r *TerraformModuleSourceVersion) isRegistrySource(source string) bool {
+	matches := registrySourcePattern.FindStringSubmatch(source)
+	if matches == nil {
+		return false
+	}
+	switch strings.ToLower(matches[1]) {
+	case "github.com", "bitbucket.org":
+		return false
+	}
+	return true
+}
+
+// checkRegistrySource checks whether the registry module is pinned by the version
+// attribute, using only the allowed operators in the version constraints.
+func (r *TerraformModuleSourceVersion) checkRegistrySource(runner tflint.Runner, module *hclext.Block, source string, allowedOperators []string, allowedVersions []*regexp.Regexp, policies []*moduleVersionPolicy, config *TerraformModuleSourceVersionConfig) error {
+	versionAttr, versionExist := module.Body.Attributes["version"]
+	if !versionExist {
+		return runner.EmitIssue(
+			r,
+			fmt.Sprintf("module '%s' source '%s' is not pinned (missing version attribute).", module.Labels[0], source),
+			module.Body.Attributes["source"].Expr.Range(),
+		)
+	}
+
+	var versionValue string
+	if err := runner.EvaluateExpr(versionAttr.Expr, &versionValue, nil); err != nil {
+		return err
+	}
+	if strings.TrimSpace(versionValue) == "" {
+		return runner.EmitIssue(
+			r,
+			fmt.Sprintf("module '%s' source '%s' is not pinned (empty version attribute).", module.Labels[0], source),
+			versionAttr.Expr.Range(),
+		)
+	}
+
+	for _, constraint := range strings.Split(versionValue, ",") {
+		matches := versionConstraintPattern.FindStringSubmatch(strings.TrimSpace(constraint))
+		if matches == nil {
+			continue
+		}
+		operator, version := matches[1], matches[2]
+		if operator == "" {
+			operator = "="
+		}
+
+		if !slices.Contains(allowedOperators, operator) {
+			if err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("module '%s' version '%s' uses operator '%s', which is not in allowed_version_operators", module.Labels[0], versionValue, operator),
+				versionAttr.Expr.Range(),
+			); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := semver.NewVersion(version); err != nil {
+			if r.isAllowedVersion(version, allowedVersions) {
 				continue
 			}
+			if err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("module '%s' source '%s' [version='%s'] does not match any allowed_versions pattern", module.Labels[0], source, version),
+				versionAttr.Expr.Range(),
+			); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.checkReleaseVersion(runner, module, source, "version", version, versionAttr.Expr.Range(), config); err != nil {
+			return err
+		}
+
+		// Version policies apply to exact versions only, as the selected version of the
+		// other constraints is not known without the registry.
+		if operator == "=" {
+			if err := r.checkVersionPolicy(runner, module, source, "version", version, versionAttr.Expr.Range(), policies, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRevisionSource checks whether the version control source is pinned by any of the
+// query parameters, to a semantic version or a version matching allowed_versions, or a
+// full commit SHA, as allowed by the pin style. Commit SHAs matching allowed_versions are
+// always allowed, and other abbreviated commit SHAs are always reported.
+func (r *TerraformModuleSourceVersion) checkRevisionSource(runner tflint.Runner, module *hclext.Block, source string, u *url.URL, keys []string, allowedVersions []*regexp.Regexp, pinStyle string) error {
+	sourceAttr := module.Body.Attributes["source"]
+
+	key, revision := r.revisionQuery(u, keys)
+	if revision == "" {
+		params := make([]string, len(keys))
+		for i, key := range keys {
+			params[i] = fmt.Sprintf("?%s=", key)
+		}
+		return runner.EmitIssue(
+			r,
+			fmt.Sprintf(`module '%s' source '%s' is not pinned (missing %s in the URL).`, module.Labels[0], source, strings.Join(params, " or ")),
+			sourceAttr.Expr.Range(),
+		)
+	}
+
+	var message string
+	switch kind := r.revisionKind(revision); {
+	case kind != revisionTag && r.isAllowedVersion(revision, allowedVersions):
+		// Commit SHAs explicitly allowed by allowed_versions are accepted in any pin style.
+	case kind == revisionShortSHA:
+		message = "is an abbreviated commit SHA, which is ambiguous (use the full commit SHA)"
+	case kind == revisionFullSHA && pinStyle == "tag":
+		message = "is a commit SHA, which is not allowed by pin_style 'tag'"
+	case kind == revisionTag && pinStyle == "sha":
+		message = "is not a full commit SHA, which is required by pin_style 'sha'"
+	case kind == revisionTag:
+		if _, err := semver.NewVersion(revision); err != nil && !r.isAllowedVersion(revision, allowedVersions) {
+			message = "does not match any allowed_versions pattern"
+		}
+	}
+	if message == "" {
+		return nil
+	}
+
+	return runner.EmitIssue(
+		r,
+		fmt.Sprintf("module '%s' source '%s' [%s='%s'] %s", module.Labels[0], source, key, revision, message),
+		sourceAttr.Expr.Range(),
+	)
+}
+
+// revisionKind returns whether the revision is a full or abbreviated commit SHA, or a tag.
+// Hexadecimal revisions shorter than 7 characters, the default abbreviation of git, are
+// considered tags.
+func (r *TerraformModuleSourceVersion) revisionKind(revision string) revisionKind {
+	switch {
+	case fullCommitPattern.MatchString(revision):
+		return revisionFullSHA
+	case len(revision) >= 7 && len(revision) < 40 && shortCommitPattern.MatchString(revision):
+		return revisionShortSHA
+	default:
+		return revisionTag
+	}
+}
+
+// checkGitMirror checks whether the ref of the git source resolves to a tag or a commit
+// in the mirrored repository. Sources without ref are already reported as not pinned.
+func (r *TerraformModuleSourceVersion) checkGitMirror(runner tflint.Runner, module *hclext.Block, source string, u *url.URL, mirror *gitMirror) error {
+	sourceAttr := module.Body.Attributes["source"]
+
+	key, revision := r.revisionQuery(u, []string{"ref", "rev"})
+	if revision == "" {
+		return nil
+	}
+
+	repo, ok := mirror.repository(u)
+	if !ok {
+		return runner.EmitIssue(
+			r,
+			fmt.Sprintf("module '%s' source '%s' is not found in git_mirror_dir", module.Labels[0], source),
+			sourceAttr.Expr.Range(),
+		)
+	}
+
+	kind, err := mirror.resolveRef(repo, revision)
+	if err != nil {
+		return err
+	}
+	var reason string
+	switch kind {
+	case gitRefNotFound:
+		reason = "does not exist in git_mirror_dir"
+	case gitRefBranch:
+		reason = "points to a branch instead of a tag"
+	default:
+		return nil
+	}
+	return runner.EmitIssue(
+		r,
+		fmt.Sprintf("module '%s' source '%s' [%s='%s'] %s", module.Labels[0], source, key, revision, reason),
+		sourceAttr.Expr.Range(),
+	)
+}
+
+// checkRevisionPolicy checks the semantic version pinned by any of the query parameters
+// against the prerelease and version policies. The tags of the mirrored repository are
+// the candidates of the newest allowed version if the mirror is available.
+func (r *TerraformModuleSourceVersion) checkRevisionPolicy(runner tflint.Runner, module *hclext.Block, source string, u *url.URL, keys []string, policies []*moduleVersionPolicy, mirror *gitMirror, config *TerraformModuleSourceVersionConfig) error {
+	key, revision := r.revisionQuery(u, keys)
+	if revision == "" {
+		return nil
+	}
+
+	if err := r.checkReleaseVersion(runner, module, source, key, revision, module.Body.Attributes["source"].Expr.Range(), config); err != nil {
+		return err
+	}
+
+	candidates := func() ([]string, error) {
+		if mirror == nil {
+			return nil, nil
 		}
+		repo, ok := mirror.repository(u)
+		if !ok {
+			return nil, nil
+		}
+		return mirror.tags(repo)
+	}
+	return r.checkVersionPolicy(runner, module, source, key, revision, module.Body.Attributes["source"].Expr.Range(), policies, candidates)
+}
 
-		_, err = semver.NewVersion(revision)
+// checkReleaseVersion checks whether the semantic version is a prerelease or has build
+// metadata. Build metadata is ignored in version precedence, so versions which differ
+// only in build metadata cannot be told apart by the version policies.
+func (r *TerraformModuleSourceVersion) checkReleaseVersion(runner tflint.Runner, module *hclext.Block, source string, key string, version string, rng hcl.Range, config *TerraformModuleSourceVersionConfig) error {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+
+	if v.Prerelease() != "" && !*config.AllowPrerelease {
+		allowed, err := r.isPrereleaseModule(runner, module, config)
 		if err != nil {
-			allowed := false
-			for _, version := range config.AllowedVersions {
-				re := regexp.MustCompile(version)
-				if re.MatchString(revision) {
-					allowed = true
-					break
-				}
+			return err
+		}
+		if !allowed {
+			if err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("module '%s' source '%s' [%s='%s'] is a prerelease version, which is only allowed in prerelease_paths or modules with env in prerelease_envs", module.Labels[0], source, key, version),
+				rng,
+			); err != nil {
+				return err
 			}
+		}
+	}
+
+	if v.Metadata() != "" && !*config.AllowBuildMetadata {
+		return runner.EmitIssue(
+			r,
+			fmt.Sprintf("module '%s' source '%s' [%s='%s'] has build metadata, which is ignored in version precedence", module.Labels[0], source, key, version),
+			rng,
+		)
+	}
+
+	return nil
+}
 
-			if !allowed {
-				if _err := runner.EmitIssue(
-					r,
-					fmt.Sprintf("module '%s' source '%s' [%s='%s'] does not match any allowed_versions pattern", module.Labels[0], sourceValue, key, revision),
-					sourceAttr.Expr.Range(),
-				); _err != nil {
-					return _err
+// isPrereleaseModule returns whether the module is declared under any of the prerelease
+// paths, or has the env attribute set to any of the prerelease envs.
+func (r *TerraformModuleSourceVersion) isPrereleaseModule(runner tflint.Runner, module *hclext.Block, config *TerraformModuleSourceVersionConfig) (bool, error) {
+	if len(config.PrereleasePaths) > 0 {
+		wd, err := runner.GetOriginalwd()
+		if err != nil {
+			return false, err
+		}
+		// File names are relative to the original working directory, so a root module run
+		// reports "main.tf" in "." and the absolute directory is needed to match it.
+		dir := module.DefRange.Filename
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(wd, dir)
+		}
+		if matchPrereleasePaths(config.PrereleasePaths, filepath.ToSlash(filepath.Dir(dir))) {
+			return true, nil
+		}
+	}
+
+	envAttr, exists := module.Body.Attributes["env"]
+	if !exists {
+		return false, nil
+	}
+	// Unknown and null values are not evaluated, and considered as not a prerelease env.
+	var allowed bool
+	err := runner.EvaluateExpr(envAttr.Expr, func(env string) error {
+		allowed = slices.Contains(config.PrereleaseEnvs, env)
+		return nil
+	}, nil)
+	return allowed, err
+}
+
+// matchPrereleasePaths returns whether the absolute directory, or any of its parents, matches
+// any of the patterns. Absolute patterns are matched against the whole path, and relative
+// patterns against as many trailing path elements as the pattern has.
+func matchPrereleasePaths(patterns []string, dir string) bool {
+	for ; ; dir = path.Dir(dir) {
+		for _, pattern := range patterns {
+			pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+			target := dir
+			if !path.IsAbs(pattern) {
+				elems := strings.Split(strings.TrimPrefix(dir, "/"), "/")
+				if n := strings.Count(pattern, "/") + 1; n < len(elems) {
+					elems = elems[len(elems)-n:]
 				}
-				continue
+				target = strings.Join(elems, "/")
+			}
+			if matched, _ := path.Match(pattern, target); matched {
+				return true
 			}
 		}
+		if parent := path.Dir(dir); parent == dir || parent == "." {
+			return false
+		}
 	}
+}
 
-	return nil
+// checkVersionPolicy checks the version against the first version policy matching the
+// source. Versions which are not semantic versions are left to allowed_versions.
+func (r *TerraformModuleSourceVersion) checkVersionPolicy(runner tflint.Runner, module *hclext.Block, source string, key string, version string, rng hcl.Range, policies []*moduleVersionPolicy, candidates func() ([]string, error)) error {
+	policy, ok := findModuleVersionPolicy(policies, source)
+	if !ok {
+		return nil
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+	reason := policy.violation(v)
+	if reason == "" {
+		return nil
+	}
+
+	message := fmt.Sprintf("module '%s' source '%s' [%s='%s'] %s", module.Labels[0], source, key, version, reason)
+	var versions []string
+	if candidates != nil {
+		if versions, err = candidates(); err != nil {
+			return err
+		}
+	}
+	if versions != nil {
+		if newest, ok := policy.newest(versions); ok {
+			message += fmt.Sprintf(" (the newest allowed version is '%s')", newest)
+		}
+	} else if minimum, ok := policy.acceptableMinimum(v); ok {
+		// Without the versions of the module, the minimum version is the only version
+		// known to be allowed, which is not necessarily the one to upgrade to.
+		message += fmt.Sprintf(" (the minimum acceptable version is '%s', newer versions are not known)", minimum)
+	}
+	return runner.EmitIssue(r, message, rng)
+}
+
+// checkArchiveSource checks whether the archive source, such as S3 and GCS objects or
+// HTTP URLs, is pinned by the checksum, or the version in the object key or filename.
+func (r *TerraformModuleSourceVersion) checkArchiveSource(runner tflint.Runner, module *hclext.Block, source string, u *url.URL, allowedVersions []*regexp.Regexp) error {
+	if u.Query().Get("checksum") != "" {
+		return nil
+	}
+
+	var latest bool
+	for _, segment := range strings.Split(u.Path, "/") {
+		segment = r.trimArchiveExtension(segment)
+		if segment == "" {
+			continue
+		}
+		if archiveVersionPattern.MatchString(segment) || r.isAllowedVersion(segment, allowedVersions) {
+			return nil
+		}
+		latest = latest || archiveLatestPattern.MatchString(segment)
+	}
+
+	reason := "missing ?checksum= or a version in the path"
+	if latest {
+		reason = "the path refers to 'latest'"
+	}
+	return runner.EmitIssue(
+		r,
+		fmt.Sprintf("module '%s' source '%s' is not pinned (%s).", module.Labels[0], source, reason),
+		module.Body.Attributes["source"].Expr.Range(),
+	)
+}
+
+// trimArchiveExtension removes the archive extension from the filename, such as
+// vpc-v1.4.0.tar.gz to vpc-v1.4.0.
+func (r *TerraformModuleSourceVersion) trimArchiveExtension(filename string) string {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(filename, ext) {
+			return strings.TrimSuffix(filename, ext)
+		}
+	}
+	return filename
+}
+
+// revisionQuery returns the first query parameter of the keys specified in the URL.
+func (r *TerraformModuleSourceVersion) revisionQuery(u *url.URL, keys []string) (string, string) {
+	query := u.Query()
+	for _, key := range keys {
+		if revision := query.Get(key); revision != "" {
+			return key, revision
+		}
+	}
+	return "", ""
+}
+
+// isAllowedVersion returns whether the version matches any allowed_versions pattern.
+func (r *TerraformModuleSourceVersion) isAllowedVersion(version string, allowedVersions []*regexp.Regexp) bool {
+	for _, re := range allowedVersions {
+		if re.MatchString(version) {
+			return true
+		}
+	}
+	return false
 }
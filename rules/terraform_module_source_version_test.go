@@ -1,6 +1,11 @@
 package rules
 
 import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -225,6 +230,803 @@ rule "terraform_module_source_version" {
 				},
 			},
 		},
+		{
+			Name: "registry module is pinned to exact version.",
+			Content: `
+module "my_module" {
+  source  = "hashicorp/consul/aws"
+  version = "0.1.0"
+}
+
+module "my_private_module" {
+  source  = "app.terraform.io/example-corp/k8s-cluster/azurerm//modules/node-pool"
+  version = "= 1.2.0"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "registry module is not pinned",
+			Content: `
+module "my_module" {
+  source = "app.terraform.io/example-corp/k8s-cluster/azurerm"
+  name   = "my_name"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'app.terraform.io/example-corp/k8s-cluster/azurerm' is not pinned (missing version attribute).",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 63},
+					},
+				},
+			},
+		},
+		{
+			Name: "registry module version is empty",
+			Content: `
+module "my_module" {
+  source  = "hashicorp/consul/aws"
+  version = " "
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'hashicorp/consul/aws' is not pinned (empty version attribute).",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 13},
+						End:      hcl.Pos{Line: 4, Column: 16},
+					},
+				},
+			},
+		},
+		{
+			Name: "registry module version uses operator which is not allowed by default.",
+			Content: `
+module "my_module" {
+  source  = "hashicorp/consul/aws"
+  version = "~> 0.1"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' version '~> 0.1' uses operator '~>', which is not in allowed_version_operators",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 13},
+						End:      hcl.Pos{Line: 4, Column: 21},
+					},
+				},
+			},
+		},
+		{
+			Name: "registry module version uses allowed operators.",
+			Content: `
+module "my_module" {
+  source  = "hashicorp/consul/aws"
+  version = "~> 0.1"
+}
+
+module "my_other_module" {
+  source  = "hashicorp/vault/aws"
+  version = ">= 0.1, < 1.0"
+}`,
+			Config: testTerraformModuleSourceVersionOperatorsConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_other_module' version '>= 0.1, < 1.0' uses operator '>=', which is not in allowed_version_operators",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 9, Column: 13},
+						End:      hcl.Pos{Line: 9, Column: 28},
+					},
+				},
+			},
+		},
+		{
+			Name: "registry module version is not semver and does not match allowed_versions.",
+			Content: `
+module "my_module" {
+  source  = "hashicorp/consul/aws"
+  version = "latest"
+}`,
+			Config: testTerraformModuleSourceVersionConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'hashicorp/consul/aws' [version='latest'] does not match any allowed_versions pattern",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 13},
+						End:      hcl.Pos{Line: 4, Column: 21},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module version is allowed by version policy.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.5.0"
+}`,
+			Config:   testTerraformModuleSourceVersionPolicyConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module version is older than the minimum version.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v0.9.0"
+}`,
+			Config: testTerraformModuleSourceVersionPolicyConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v0.9.0' [ref='v0.9.0'] is older than the minimum version '1.0.0'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 77},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module version is blocked.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.3.1"
+}`,
+			Config: testTerraformModuleSourceVersionPolicyConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.3.1' [ref='v1.3.1'] is blocked",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 77},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module version is deprecated.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.2.0"
+}`,
+			Config: testTerraformModuleSourceVersionPolicyConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.2.0' [ref='v1.2.0'] is deprecated: subnets are not tagged",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 77},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module of other source is not checked by version policy.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/other-module.git?ref=v0.1.0"
+}`,
+			Config:   testTerraformModuleSourceVersionPolicyConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "registry module version is blocked.",
+			Content: `
+module "my_module" {
+  source  = "hashicorp/consul/aws"
+  version = "0.11.0"
+}`,
+			Config: testTerraformModuleSourceVersionPolicyConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'hashicorp/consul/aws' [version='0.11.0'] is blocked",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 13},
+						End:      hcl.Pos{Line: 4, Column: 21},
+					},
+				},
+			},
+		},
+		{
+			Name: "registry module version is older than the minimum version.",
+			Content: `
+module "my_module" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "4.0.0"
+}`,
+			Config: testTerraformModuleSourceVersionPolicyConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'terraform-aws-modules/vpc/aws' [version='4.0.0'] is older than the minimum version '5.0.0' (the minimum acceptable version is '5.0.0', newer versions are not known)",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 13},
+						End:      hcl.Pos{Line: 4, Column: 20},
+					},
+				},
+			},
+		},
+		{
+			Name: "module source uses allowed host and protocol.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0"
+}
+
+module "my_archive" {
+  source = "s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc/v1.4.0.zip"
+}
+
+module "my_local" {
+  source = "./test"
+}`,
+			Config:   testTerraformModuleSourceVersionLocationConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "module source uses denied host.",
+			Content: `
+module "my_module" {
+  source = "github.com/hashicorp/example?ref=v1.0.0"
+}`,
+			Config: testTerraformModuleSourceVersionLocationConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'github.com/hashicorp/example?ref=v1.0.0' uses host 'github.com', which is not in allowed_hosts",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 53},
+					},
+				},
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'github.com/hashicorp/example?ref=v1.0.0' uses host 'github.com', which is in denied_hosts",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 53},
+					},
+				},
+			},
+		},
+		{
+			Name: "module source uses protocol which is not allowed.",
+			Content: `
+module "my_module" {
+  source = "git@gitlab.example.com:test/test-module.git?ref=v1.0.0"
+}`,
+			Config: testTerraformModuleSourceVersionLocationConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git@gitlab.example.com:test/test-module.git?ref=v1.0.0' uses protocol 'git::ssh', which is not in allowed_protocols",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 68},
+					},
+				},
+			},
+		},
+		{
+			Name: "module source uses plain https without forced getter.",
+			Content: `
+module "my_module" {
+  source = "https://gitlab.example.com/test/test-module/v1.0.0.zip"
+}`,
+			Config: testTerraformModuleSourceVersionLocationConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'https://gitlab.example.com/test/test-module/v1.0.0.zip' uses protocol 'https', which is not in allowed_protocols",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 68},
+					},
+				},
+			},
+		},
+		{
+			Name: "registry module source uses host which is not allowed.",
+			Content: `
+module "my_module" {
+  source  = "hashicorp/consul/aws"
+  version = "0.1.0"
+}`,
+			Config: testTerraformModuleSourceVersionLocationConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'hashicorp/consul/aws' uses host 'registry.terraform.io', which is not in allowed_hosts",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 13},
+						End:      hcl.Pos{Line: 3, Column: 35},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module is pinned to a full commit SHA with tag_or_full_sha pin style.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=0123456789abcdef0123456789abcdef01234567"
+}`,
+			Config: `
+rule "terraform_module_source_version" {
+  enabled   = true
+  pin_style = "tag_or_full_sha"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module is pinned to commit SHAs matching allowed_versions.",
+			Content: `
+module "my_module_1" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=0123456789abcdef0123456789abcdef01234567"
+}
+
+module "my_module_2" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=0123abc"
+}`,
+			Config: `
+rule "terraform_module_source_version" {
+  enabled          = true
+  allowed_versions = ["^0123"]
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module is pinned to a version of digits only.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=1234567"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module is pinned to an abbreviated commit SHA.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=0123abc"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=0123abc' [ref='0123abc'] is an abbreviated commit SHA, which is ambiguous (use the full commit SHA)",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 78},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module is pinned to a commit SHA with the default tag pin style.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=0123456789abcdef0123456789abcdef01234567"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=0123456789abcdef0123456789abcdef01234567' [ref='0123456789abcdef0123456789abcdef01234567'] is a commit SHA, which is not allowed by pin_style 'tag'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 111},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module is pinned to a commit SHA with sha pin style.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=0123456789abcdef0123456789abcdef01234567"
+}`,
+			Config: `
+rule "terraform_module_source_version" {
+  enabled   = true
+  pin_style = "sha"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module is pinned to a tag with sha pin style.",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0"
+}`,
+			Config: `
+rule "terraform_module_source_version" {
+  enabled   = true
+  pin_style = "sha"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0' [ref='v1.0.0'] is not a full commit SHA, which is required by pin_style 'sha'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 77},
+					},
+				},
+			},
+		},
+		{
+			Name: "github module in registry form is not a registry module.",
+			Content: `
+module "my_module" {
+  source = "github.com/hashicorp/example?ref=v1.0.0"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "archive modules are pinned to version in the path or checksum.",
+			Content: `
+module "s3_module" {
+  source = "s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc/v1.4.0.zip"
+}
+
+module "gcs_module" {
+  source = "gcs::https://www.googleapis.com/storage/v1/modules/vpc/1.4.0/vpc.zip"
+}
+
+module "http_module" {
+  source = "https://example.com/vpc-module/vpc-v1.4.0.tar.gz"
+}
+
+module "checksum_module" {
+  source = "https://example.com/vpc-module/vpc.zip?archive=zip&checksum=sha256:2a1f6e7b"
+}
+
+module "hg_module" {
+  source = "hg::http://example.com/vpc-module?rev=v1.4.0"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "archive modules are not pinned.",
+			Content: `
+module "s3_module" {
+  source = "examplecorp-terraform-modules.s3-eu-west-1.amazonaws.com/vpc/latest.zip"
+}
+
+module "gcs_module" {
+  source = "gcs::https://www.googleapis.com/storage/v1/modules/vpc.zip"
+}
+
+module "http_module" {
+  source = "https://example.com/vpc-module/latest/vpc.tar.gz?archive=tar.gz"
+}
+
+module "hg_module" {
+  source = "hg::http://example.com/vpc-module"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 's3_module' source 'examplecorp-terraform-modules.s3-eu-west-1.amazonaws.com/vpc/latest.zip' is not pinned (the path refers to 'latest').",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 85},
+					},
+				},
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'gcs_module' source 'gcs::https://www.googleapis.com/storage/v1/modules/vpc.zip' is not pinned (missing ?checksum= or a version in the path).",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 7, Column: 12},
+						End:      hcl.Pos{Line: 7, Column: 72},
+					},
+				},
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'http_module' source 'https://example.com/vpc-module/latest/vpc.tar.gz?archive=tar.gz' is not pinned (the path refers to 'latest').",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 11, Column: 12},
+						End:      hcl.Pos{Line: 11, Column: 77},
+					},
+				},
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'hg_module' source 'hg::http://example.com/vpc-module' is not pinned (missing ?rev= in the URL).",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 15, Column: 12},
+						End:      hcl.Pos{Line: 15, Column: 47},
+					},
+				},
+			},
+		},
+		{
+			Name: "archive module is pinned to version matching allowed_versions.",
+			Content: `
+module "my_module" {
+  source = "https://example.com/vpc-module/release-42.zip"
+}`,
+			Config: `
+rule "terraform_module_source_version" {
+  enabled          = true
+  allowed_versions = ["^release-\\d+$"]
+}
+`,
+			Expected: helper.Issues{},
+		},
+	}
+
+	rule := NewTerraformModuleSourceVersion()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf":     test.Content,
+				".tflint.hcl": test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformModuleDependencies_JSON(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Config   string
+		Expected helper.Issues
+	}{
+		{
+			Name: "git module reference is pinned to semver.",
+			Content: `{
+  "module": {
+    "my_module": {
+      "source": "git::https://gitlab.example.com/test/test-module.git?ref=v1.2.0",
+      "name": "my_name"
+    }
+  }
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module is not pinned",
+			Content: `{
+  "module": {
+    "my_module": {
+      "source": "git::https://gitlab.example.com/test/test-module.git",
+      "name": "my_name"
+    }
+  }
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git' is not pinned (missing ?ref= or ?rev= in the URL).",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 4, Column: 17},
+						End:      hcl.Pos{Line: 4, Column: 71},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformModuleSourceVersion()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf.json": test.Content,
+				".tflint.hcl":  test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformModuleDependencies_GitMirror(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Mirror a repository with a tag and a branch, as an air-gapped CI would do.
+	work := t.TempDir()
+	root := t.TempDir()
+	mirror := filepath.Join(root, "mirror")
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = work
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git %v: %s", args, err)
+		}
+		return string(out)
+	}
+	git("init", "--quiet", ".")
+	git("commit", "--quiet", "--allow-empty", "-m", "initial commit")
+	git("tag", "v1.0.0")
+	git("tag", "v1.2.3")
+	git("tag", "v1.3.0-rc1")
+	git("tag", "v2.0.0")
+	git("branch", "develop")
+	commit := git("rev-parse", "HEAD")[:40]
+	git("clone", "--quiet", "--bare", work, filepath.Join(mirror, "gitlab.example.com", "test", "test-module.git"))
+	// A repository outside the mirror must not be reachable through "..".
+	git("clone", "--quiet", "--bare", work, filepath.Join(root, "outside.git"))
+	// A directory which is not a git repository makes git fail.
+	if err := os.MkdirAll(filepath.Join(mirror, "gitlab.example.com", "test", "broken-module.git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := fmt.Sprintf(`
+rule "terraform_module_source_version" {
+  enabled          = true
+  allowed_versions = ["^develop$"]
+  pin_style        = "tag_or_full_sha"
+  git_mirror_dir   = %q
+
+  version_policy {
+    source           = "gitlab\\.example\\.com/test/test-module"
+    minimum_version  = "1.1.0"
+    blocked_versions = [">= 2.0.0"]
+  }
+}
+`, mirror)
+
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+		Error    bool
+	}{
+		{
+			Name: "git module pinned to a mirrored tag",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git//modules/vpc?ref=v1.2.3"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module pinned to a mirrored commit",
+			Content: fmt.Sprintf(`
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module?ref=%s"
+}`, commit),
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "git module pinned to a tag older than the minimum version",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0' [ref='v1.0.0'] is older than the minimum version '1.1.0' (the newest allowed version is 'v1.2.3')",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 77},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module pinned to a missing tag",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.2.4"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.2.4' [ref='v1.2.4'] does not exist in git_mirror_dir",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 77},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module pinned to a branch",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=develop"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=develop' [ref='develop'] points to a branch instead of a tag",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 78},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module not mirrored",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/other-module.git?ref=v1.2.3"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/other-module.git?ref=v1.2.3' is not found in git_mirror_dir",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 78},
+					},
+				},
+			},
+		},
+		{
+			Name: "git module outside the mirror",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/../../outside.git?ref=v1.2.3"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/../../outside.git?ref=v1.2.3' is not found in git_mirror_dir",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 74},
+					},
+				},
+			},
+		},
+		{
+			Name: "git fails in the mirror",
+			Content: `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/broken-module.git?ref=v1.2.3"
+}`,
+			Expected: helper.Issues{},
+			Error:    true,
+		},
 	}
 
 	rule := NewTerraformModuleSourceVersion()
@@ -232,7 +1034,247 @@ rule "terraform_module_source_version" {
 		t.Run(test.Name, func(t *testing.T) {
 			runner := helper.TestRunner(t, map[string]string{
 				"main.tf":     test.Content,
-				".tflint.hcl": test.Config,
+				".tflint.hcl": config,
+			})
+
+			err := rule.Check(runner)
+			if test.Error && err == nil {
+				t.Fatal("Expected error but got nil")
+			}
+			if !test.Error && err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformModuleDependencies_Prerelease(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Files    map[string]string
+		Config   string
+		Expected helper.Issues
+	}{
+		{
+			Name: "prerelease is allowed by default",
+			Files: map[string]string{
+				"main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1"
+}`,
+			},
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "prerelease is not allowed",
+			Files: map[string]string{
+				"main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1"
+}
+
+module "my_registry_module" {
+  source  = "hashicorp/consul/aws"
+  version = "0.12.0-beta1"
+}`,
+			},
+			Config: testTerraformModuleSourceVersionPrereleaseConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1' [ref='v1.0.0-rc1'] is a prerelease version, which is only allowed in prerelease_paths or modules with env in prerelease_envs",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 81},
+					},
+				},
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_registry_module' source 'hashicorp/consul/aws' [version='0.12.0-beta1'] is a prerelease version, which is only allowed in prerelease_paths or modules with env in prerelease_envs",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 8, Column: 13},
+						End:      hcl.Pos{Line: 8, Column: 27},
+					},
+				},
+			},
+		},
+		{
+			Name: "prerelease is allowed in module with dev env",
+			Files: map[string]string{
+				"main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1"
+  env    = "dev"
+}`,
+			},
+			Config:   testTerraformModuleSourceVersionPrereleaseConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "prerelease is not allowed in module with prod env",
+			Files: map[string]string{
+				"main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1"
+  env    = "prod"
+}`,
+			},
+			Config: testTerraformModuleSourceVersionPrereleaseConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1' [ref='v1.0.0-rc1'] is a prerelease version, which is only allowed in prerelease_paths or modules with env in prerelease_envs",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 81},
+					},
+				},
+			},
+		},
+		{
+			Name: "prerelease is allowed under prerelease paths",
+			Files: map[string]string{
+				"environments/dev-eu/app/main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1"
+}`,
+			},
+			Config:   testTerraformModuleSourceVersionPrereleaseConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "prerelease is not allowed outside prerelease paths",
+			Files: map[string]string{
+				"environments/prod/main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1"
+}`,
+			},
+			Config: testTerraformModuleSourceVersionPrereleaseConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1' [ref='v1.0.0-rc1'] is a prerelease version, which is only allowed in prerelease_paths or modules with env in prerelease_envs",
+					Range: hcl.Range{
+						Filename: "environments/prod/main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 81},
+					},
+				},
+			},
+		},
+		{
+			Name: "build metadata is not allowed",
+			Files: map[string]string{
+				"main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0%2Bbuild5"
+}`,
+			},
+			Config: `
+rule "terraform_module_source_version" {
+  enabled              = true
+  allow_build_metadata = false
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0%2Bbuild5' [ref='v1.0.0+build5'] has build metadata, which is ignored in version precedence",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 86},
+					},
+				},
+			},
+		},
+		{
+			Name: "build metadata is allowed by default",
+			Files: map[string]string{
+				"main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0%2Bbuild5"
+}`,
+			},
+			Expected: helper.Issues{},
+		},
+	}
+
+	rule := NewTerraformModuleSourceVersion()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			files := map[string]string{".tflint.hcl": test.Config}
+			maps.Copy(files, test.Files)
+			runner := helper.TestRunner(t, files)
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformModuleDependencies_PrereleaseRootModule(t *testing.T) {
+	// TFLint reports the files of a root module run relative to the working directory,
+	// such as "main.tf", so the patterns are matched against the working directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name     string
+		Pattern  string
+		Expected helper.Issues
+	}{
+		{
+			Name:     "prerelease is allowed when the working directory matches",
+			Pattern:  filepath.Base(wd),
+			Expected: helper.Issues{},
+		},
+		{
+			Name:     "prerelease is allowed when the working directory matches an absolute path",
+			Pattern:  filepath.ToSlash(wd),
+			Expected: helper.Issues{},
+		},
+		{
+			Name:    "prerelease is not allowed when the working directory does not match",
+			Pattern: "environments/dev-*",
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleSourceVersion(),
+					Message: "module 'my_module' source 'git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1' [ref='v1.0.0-rc1'] is a prerelease version, which is only allowed in prerelease_paths or modules with env in prerelease_envs",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 81},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformModuleSourceVersion()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				".tflint.hcl": fmt.Sprintf(`
+rule "terraform_module_source_version" {
+  enabled          = true
+  allow_prerelease = false
+  prerelease_paths = [%q]
+}`, test.Pattern),
+				"main.tf": `
+module "my_module" {
+  source = "git::https://gitlab.example.com/test/test-module.git?ref=v1.0.0-rc1"
+}`,
 			})
 
 			if err := rule.Check(runner); err != nil {
@@ -250,3 +1292,52 @@ rule "terraform_module_source_version" {
   allowed_versions = ["^bugfix/\\d+$", "^feature/\\d+$"]
 }
 `
+
+const testTerraformModuleSourceVersionPolicyConfig = `
+rule "terraform_module_source_version" {
+  enabled = true
+
+  version_policy {
+    source           = "gitlab\\.example\\.com/test/test-module"
+    minimum_version  = "1.0.0"
+    blocked_versions = ["1.3.1"]
+    deprecated_versions = {
+      "< 1.4.0" = "subnets are not tagged"
+    }
+  }
+
+  version_policy {
+    source           = "^hashicorp/consul/"
+    blocked_versions = [">= 0.11.0, < 0.12.0"]
+  }
+
+  version_policy {
+    source          = "^terraform-aws-modules/vpc/"
+    minimum_version = "5.0.0"
+  }
+}
+`
+
+const testTerraformModuleSourceVersionLocationConfig = `
+rule "terraform_module_source_version" {
+  enabled           = true
+  allowed_hosts     = ["gitlab.example.com", "*.amazonaws.com"]
+  denied_hosts      = ["github.com"]
+  allowed_protocols = ["git::https", "s3::https"]
+}
+`
+
+const testTerraformModuleSourceVersionPrereleaseConfig = `
+rule "terraform_module_source_version" {
+  enabled          = true
+  allow_prerelease = false
+  prerelease_paths = ["environments/dev-*"]
+}
+`
+
+const testTerraformModuleSourceVersionOperatorsConfig = `
+rule "terraform_module_source_version" {
+  enabled                   = true
+  allowed_version_operators = ["=", "~>", "<"]
+}
+`
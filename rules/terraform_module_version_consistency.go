@@ -0,0 +1,155 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/myklst/tflint-ruleset-myklst/project"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// TerraformModuleVersionConsistency checks whether modules from the same repository are pinned to the same ref
+type TerraformModuleVersionConsistency struct {
+	tflint.DefaultRule
+}
+
+type terraformModuleVersionConsistencyConfig struct {
+	IgnoreModules []string `hclext:"ignore_modules,optional"`
+}
+
+// pinnedModule is a module sourced from a version control repository with the ref.
+type pinnedModule struct {
+	module *hclext.Block
+	ref    string
+}
+
+// NewTerraformModuleVersionConsistency returns a new rule
+func NewTerraformModuleVersionConsistency() *TerraformModuleVersionConsistency {
+	return &TerraformModuleVersionConsistency{}
+}
+
+// Name returns the rule name
+func (r *TerraformModuleVersionConsistency) Name() string {
+	return "terraform_module_version_consistency"
+}
+
+// Enabled returns whether the rule is enabled by default
+func (r *TerraformModuleVersionConsistency) Enabled() bool {
+	return true
+}
+
+// Severity returns the rule severity
+func (r *TerraformModuleVersionConsistency) Severity() tflint.Severity {
+	return tflint.WARNING
+}
+
+// Link returns the rule reference link
+func (r *TerraformModuleVersionConsistency) Link() string {
+	return project.ReferenceLink(r.Name())
+}
+
+// Check checks whether all modules sourced from the same repository use the same ref
+func (r *TerraformModuleVersionConsistency) Check(runner tflint.Runner) error {
+	config := &terraformModuleVersionConsistencyConfig{}
+
+	if err := decodeRuleConfig(runner, r, config); err != nil {
+		return err
+	}
+
+	modules, err := runner.GetModuleContent(&hclext.BodySchema{
+		Blocks: []hclext.BlockSchema{
+			{
+				Type:       "module",
+				LabelNames: []string{"name"},
+				Body: &hclext.BodySchema{
+					Attributes: []hclext.AttributeSchema{
+						{Name: "source"},
+					},
+				},
+			},
+		},
+	}, &tflint.GetModuleContentOption{ExpandMode: tflint.ExpandModeNone})
+	if err != nil {
+		return err
+	}
+
+	// Group the modules by repository, ignoring the ref and subdirectory.
+	repositories := map[string][]pinnedModule{}
+	for _, module := range modules.Blocks {
+		if slices.Contains(config.IgnoreModules, module.Labels[0]) {
+			continue
+		}
+
+		sourceAttr, exists := module.Body.Attributes["source"]
+		if !exists {
+			continue
+		}
+
+		var sourceValue string
+		if err := runner.EvaluateExpr(sourceAttr.Expr, &sourceValue, nil); err != nil {
+			return err
+		}
+
+		source, err := getter.Detect(sourceValue, filepath.Dir(module.DefRange.Filename), moduleSourceDetectors)
+		if err != nil {
+			return err
+		}
+		// Invalid URLs are reported by terraform_module_source_version.
+		scheme, u, err := parseModuleSourceURL(source)
+		if err != nil {
+			continue
+		}
+
+		var ref string
+		switch scheme {
+		case "git":
+			ref = u.Query().Get("ref")
+			if ref == "" {
+				ref = u.Query().Get("rev")
+			}
+		case "hg":
+			ref = u.Query().Get("rev")
+		default:
+			continue
+		}
+		// Unpinned sources are reported by terraform_module_source_version.
+		if ref == "" {
+			continue
+		}
+
+		repository := moduleSourceRepository(u)
+		repositories[repository] = append(repositories[repository], pinnedModule{module: module, ref: ref})
+	}
+
+	for _, repository := range slices.Sorted(maps.Keys(repositories)) {
+		pinned := repositories[repository]
+
+		distinct := map[string]bool{}
+		refs := make([]string, len(pinned))
+		for i, p := range pinned {
+			distinct[p.ref] = true
+			refs[i] = fmt.Sprintf("%s (%s)", p.ref, p.module.Labels[0])
+		}
+		if len(distinct) == 1 {
+			continue
+		}
+		slices.Sort(refs)
+
+		for _, p := range pinned {
+			if err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("repository '%s' of module '%s' is pinned to multiple refs: %s", repository, p.module.Labels[0], strings.Join(refs, ", ")),
+				p.module.Body.Attributes["source"].Expr.Range(),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/helper"
+)
+
+func Test_TerraformModuleVersionConsistency(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Config   string
+		Expected helper.Issues
+	}{
+		{
+			Name: "modules from the same repository pinned to the same ref",
+			Content: `
+module "vpc" {
+  source = "git::https://gitlab.example.com/network/modules.git//vpc?ref=v1.2.0"
+}
+
+module "vpc_peering" {
+  source = "git::https://gitlab.example.com/network/modules//vpc-peering?ref=v1.2.0"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "modules from different repositories pinned to different refs",
+			Content: `
+module "vpc" {
+  source = "git::https://gitlab.example.com/network/vpc.git?ref=v1.2.0"
+}
+
+module "eks" {
+  source = "git::https://gitlab.example.com/compute/eks.git?ref=v3.0.0"
+}
+
+module "local" {
+  source = "./modules/local"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "modules from the same repository pinned to different refs",
+			Content: `
+module "vpc" {
+  source = "git::https://gitlab.example.com/network/modules.git//vpc?ref=v1.2.0"
+}
+
+module "vpc_peering" {
+  source = "git::https://gitlab.example.com/network/modules.git//vpc-peering?ref=v1.4.0"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleVersionConsistency(),
+					Message: "repository 'gitlab.example.com/network/modules' of module 'vpc' is pinned to multiple refs: v1.2.0 (vpc), v1.4.0 (vpc_peering)",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 81},
+					},
+				},
+				{
+					Rule:    NewTerraformModuleVersionConsistency(),
+					Message: "repository 'gitlab.example.com/network/modules' of module 'vpc_peering' is pinned to multiple refs: v1.2.0 (vpc), v1.4.0 (vpc_peering)",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 7, Column: 12},
+						End:      hcl.Pos{Line: 7, Column: 89},
+					},
+				},
+			},
+		},
+		{
+			Name: "github modules in different forms pinned to different refs",
+			Content: `
+module "example" {
+  source = "github.com/hashicorp/example?ref=v1.0.0"
+}
+
+module "example_git" {
+  source = "git::https://github.com/hashicorp/example.git?ref=v1.1.0"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformModuleVersionConsistency(),
+					Message: "repository 'github.com/hashicorp/example' of module 'example' is pinned to multiple refs: v1.0.0 (example), v1.1.0 (example_git)",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 3, Column: 53},
+					},
+				},
+				{
+					Rule:    NewTerraformModuleVersionConsistency(),
+					Message: "repository 'github.com/hashicorp/example' of module 'example_git' is pinned to multiple refs: v1.0.0 (example), v1.1.0 (example_git)",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 7, Column: 12},
+						End:      hcl.Pos{Line: 7, Column: 70},
+					},
+				},
+			},
+		},
+		{
+			Name: "module pinned to a different ref is ignored",
+			Content: `
+module "vpc" {
+  source = "git::https://gitlab.example.com/network/modules.git//vpc?ref=v1.2.0"
+}
+
+module "vpc_peering" {
+  source = "git::https://gitlab.example.com/network/modules.git//vpc-peering?ref=v1.4.0"
+}`,
+			Config: `
+rule "terraform_module_version_consistency" {
+  enabled        = true
+  ignore_modules = ["vpc_peering"]
+}`,
+			Expected: helper.Issues{},
+		},
+	}
+
+	rule := NewTerraformModuleVersionConsistency()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf":     test.Content,
+				".tflint.hcl": test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
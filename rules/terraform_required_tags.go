@@ -2,15 +2,17 @@ package rules
 
 import (
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/myklst/tflint-ruleset-myklst/project"
+	"github.com/myklst/tflint-ruleset-myklst/providerschema"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 type TerraformRequiredTags struct {
@@ -23,8 +25,111 @@ func NewTerraformRequiredTags() *TerraformRequiredTags {
 }
 
 type terraformRequiredTagsConfig struct {
-	Tags              []string `hclext:"tags,optional"`
-	ExcludedResources []string `hclext:"excluded_resources,optional"`
+	Tags              []string                              `hclext:"tags,optional"`
+	ExcludedResources []string                              `hclext:"excluded_resources,optional"`
+	TagValues         []terraformRequiredTagValuesConfig    `hclext:"tag_values,block"`
+	TagAttributes     []terraformRequiredTagAttributeConfig `hclext:"tag_attribute,block"`
+	// ProvidersSchemaFile is the output of `terraform providers schema -json`, which
+	// overrides the embedded list of taggable resource types.
+	ProvidersSchemaFile string `hclext:"providers_schema_file,optional"`
+
+	tagValues     map[string]*tagValueConstraint
+	tagAttributes map[string]*tagAttribute
+	schemas       *providerschema.Schemas
+}
+
+// terraformRequiredTagValuesConfig is a `tag_values` block, which restricts the values
+// of the tag key in the label to either the list of values or the regular expression.
+type terraformRequiredTagValuesConfig struct {
+	Key    string   `hclext:"key,label"`
+	Values []string `hclext:"values,optional"`
+	Regex  string   `hclext:"regex,optional"`
+}
+
+// tagValueConstraint is a compiled `tag_values` block.
+type tagValueConstraint struct {
+	values []string
+	regex  *regexp.Regexp
+}
+
+// resourceTagSet is the tags set in a tag attribute, and the range to report issues.
+// resolved is false if some of the tags cannot be resolved statically, such as tags
+// from a variable without a default, so that the tags may include more keys.
+type resourceTagSet struct {
+	tags     []resourceTag
+	rng      hcl.Range
+	resolved bool
+}
+
+// resourceTag is a tag key with the value, which is unknown if it cannot be
+// resolved statically. list is true if the tag is a "key:value" string in a list.
+type resourceTag struct {
+	key   string
+	value cty.Value
+	list  bool
+}
+
+func (c *terraformRequiredTagsConfig) validate() error {
+	// Set default required tags if none are specified
+	if len(c.Tags) == 0 {
+		c.Tags = []string{
+			"brand",
+			"env",
+			"project",
+			"devops_project_kind",
+			"devops_project_group",
+			"devops_project_name",
+		}
+	}
+
+	c.tagValues = make(map[string]*tagValueConstraint, len(c.TagValues))
+	for _, tagValues := range c.TagValues {
+		key := fmt.Sprintf("tag_values[%q]", tagValues.Key)
+		if _, exists := c.tagValues[tagValues.Key]; exists {
+			return configErrorf(key, "duplicate block for the tag key")
+		}
+
+		constraint := &tagValueConstraint{values: tagValues.Values}
+		switch {
+		case len(tagValues.Values) > 0 && tagValues.Regex != "":
+			return configErrorf(key, "only one of `values` and `regex` can be set")
+		case len(tagValues.Values) == 0 && tagValues.Regex == "":
+			return configErrorf(key, "either `values` or `regex` is required")
+		case tagValues.Regex != "":
+			re, err := regexp.Compile(tagValues.Regex)
+			if err != nil {
+				return configErrorf(key+".regex", "%s", err)
+			}
+			constraint.regex = re
+		}
+		c.tagValues[tagValues.Key] = constraint
+	}
+
+	var err error
+	if c.tagAttributes, err = newTagAttributes(c.TagAttributes); err != nil {
+		return err
+	}
+	if c.ProvidersSchemaFile != "" {
+		if c.schemas, err = providerschema.Read(c.ProvidersSchemaFile); err != nil {
+			return configErrorf("providers_schema_file", "%s", err)
+		}
+	}
+	return nil
+}
+
+// violation returns the reason why the value is not allowed, or an empty string if
+// the value is allowed.
+func (c *tagValueConstraint) violation(value string) string {
+	if c.regex != nil {
+		if !c.regex.MatchString(value) {
+			return fmt.Sprintf("which does not match '%s'", c.regex)
+		}
+		return ""
+	}
+	if !slices.Contains(c.values, value) {
+		return fmt.Sprintf("which is not one of ['%s']", strings.Join(c.values, "', '"))
+	}
+	return ""
 }
 
 // Name returns the rule name
@@ -51,33 +156,19 @@ func (r *TerraformRequiredTags) Link() string {
 func (r *TerraformRequiredTags) Check(runner tflint.Runner) error {
 	config := &terraformRequiredTagsConfig{}
 
-	if err := runner.DecodeRuleConfig(r.Name(), config); err != nil {
+	if err := decodeRuleConfig(runner, r, config); err != nil {
 		return err
 	}
 
-	// Set default required tags if none are specified
-	if len(config.Tags) == 0 {
-		config.Tags = []string{
-			"brand",
-			"env",
-			"project",
-			"devops_project_kind",
-			"devops_project_group",
-			"devops_project_name",
-		}
-	}
-
-	// Parse resources and check their `tags` blocks
+	// Parse resources and check their tag attributes, such as `tags` and `labels`
+	resourceSchema := tagAttributesSchema(config.tagAttributes)
+	resourceSchema.Attributes = append(resourceSchema.Attributes, hclext.AttributeSchema{Name: "provider"})
 	resources, err := runner.GetModuleContent(&hclext.BodySchema{
 		Blocks: []hclext.BlockSchema{
 			{
 				Type:       "resource",
 				LabelNames: []string{"type", "name"},
-				Body: &hclext.BodySchema{
-					Attributes: []hclext.AttributeSchema{
-						{Name: "tags"},
-					},
-				},
+				Body:       resourceSchema,
 			},
 		},
 	}, &tflint.GetModuleContentOption{ExpandMode: tflint.ExpandModeNone})
@@ -85,23 +176,59 @@ func (r *TerraformRequiredTags) Check(runner tflint.Runner) error {
 		return err
 	}
 
+	resolver, err := newTagResolver(runner)
+	if err != nil {
+		return err
+	}
+	// Local variables referring to each other cannot be resolved by Terraform either.
+	for _, cycle := range resolver.cycles {
+		if err := runner.EmitIssue(
+			r,
+			fmt.Sprintf("local variables ['%s'] refer to each other in a cycle", strings.Join(cycle, "', '")),
+			resolver.locals[cycle[0]].Range,
+		); err != nil {
+			return err
+		}
+	}
+
+	defaultTags, err := r.providerDefaultTags(runner, resolver)
+	if err != nil {
+		return err
+	}
+
 	for _, resource := range resources.Blocks {
 		// If the resource is stated in excluded_resources, then ignore checking.
 		if slices.Contains(config.ExcludedResources, resource.Labels[0]) || slices.Contains(config.ExcludedResources, fmt.Sprintf("%s.%s", resource.Labels[0], resource.Labels[1])) {
 			continue
 		}
 
-		// If the resource do not have attribute "tags", then ignore checking.
-		tagsAttr, tagsExist := resource.Body.Attributes["tags"]
-		if !tagsExist {
-			continue
+		// If the resource do not have the tag attribute, then only check the resource
+		// types which support tags, with the tags of the provider `default_tags`.
+		tagAttr := findTagAttribute(config.tagAttributes, resource.Labels[0])
+		tagSet, err := r.resourceTags(runner, resolver, tagAttr, resource)
+		if err != nil {
+			return err
+		}
+		untagged := tagSet == nil
+		if untagged {
+			if !isTaggable(config.schemas, resource.Labels[0], tagAttr) {
+				continue
+			}
+			tagSet = &resourceTagSet{rng: resource.DefRange, resolved: true}
+		}
+		tags, tagsRange := tagSet.tags, tagSet.rng
+		resolved := tagSet.resolved
+		// Tags of the provider `default_tags` are applied to the tags attribute, but
+		// not to tags in blocks such as `tag` of aws_autoscaling_group.
+		if providerTags, exists := defaultTags[r.resourceProvider(resource)]; exists && tagAttr.format != tagFormatBlock {
+			tags = slices.Concat(providerTags.tags, tags)
+			resolved = resolved && providerTags.resolved
 		}
 
 		// tagKeys is used to compare with required_tags to check any missing tags.
 		var tagKeys []string
-		tagKeys, err = r.traverseSearchExpr(runner, tagsAttr.Expr)
-		if err != nil {
-			return err
+		for _, tag := range tags {
+			tagKeys = append(tagKeys, tag.key)
 		}
 
 		// Remove any duplicated keys if any
@@ -113,234 +240,264 @@ func (r *TerraformRequiredTags) Check(runner tflint.Runner) error {
 			}
 		}
 
+		// If resource is AWS Cloud resource, check if `Name` tag key exists
+		missingName := r.isAwsResource(resource.Labels[0]) && !slices.Contains(tagKeys, "Name")
+
+		// The tags which cannot be resolved may include the missing tags, so they are
+		// reported as unresolved rather than missing.
+		if !resolved {
+			if missingName {
+				missing = append(missing, "Name")
+			}
+			if len(missing) > 0 {
+				if err := runner.EmitIssue(
+					r,
+					fmt.Sprintf("could not resolve tags statically for resource '%s.%s', which may be missing required tags: ['%s']", resource.Labels[0], resource.Labels[1], strings.Join(missing, "', '")),
+					tagsRange,
+				); err != nil {
+					return err
+				}
+			}
+			missing, missingName = nil, false
+		}
+
 		// Output linting error if any missing tags are present
 		if len(missing) > 0 {
+			message := fmt.Sprintf("resource '%s.%s' is missing required tags: ['%s']", resource.Labels[0], resource.Labels[1], strings.Join(missing, "', '"))
+			if untagged {
+				message = fmt.Sprintf("resource '%s.%s' does not set '%s' and is missing required tags: ['%s']", resource.Labels[0], resource.Labels[1], tagAttr.name(), strings.Join(missing, "', '"))
+			}
 			err := runner.EmitIssue(
 				r,
-				fmt.Sprintf("resource '%s.%s' is missing required tags: ['%s']", resource.Labels[0], resource.Labels[1], strings.Join(missing, "', '")),
-				tagsAttr.Expr.Range(),
+				message,
+				tagsRange,
 			)
 			if err != nil {
 				return err
 			}
 		}
 
-		// If resource is AWS Cloud resource, check if `Name` tag key exists
-		if r.isAwsResource(resource.Labels[0]) && !slices.Contains(tagKeys, "Name") {
+		if missingName {
 			err := runner.EmitIssue(
 				r,
 				fmt.Sprintf("aws resources must have 'Name' tag: '%s.%s'", resource.Labels[0], resource.Labels[1]),
-				tagsAttr.Expr.Range(),
+				tagsRange,
 			)
 			if err != nil {
 				return err
 			}
 		}
-	}
-	return nil
-}
 
-// Function to determine whether resource has `aws_` prefix
-func (r *TerraformRequiredTags) isAwsResource(resource string) bool {
-	return strings.HasPrefix(resource, "aws_")
-}
-
-// This function will perform a deep traverse into every nested local variables
-// used, check the value of tags and invoke different logics to evaluate.
-func (r *TerraformRequiredTags) traverseSearchExpr(runner tflint.Runner, expr hcl.Expression) ([]string, error) {
-	var tagKeys []string
-	// Check the value of tags and invoke different logics to evaluate.
-	switch expr := expr.(type) {
-	// Usage of function calls like merge(local.tags, { ... })
-	case *hclsyntax.FunctionCallExpr:
-		switch expr.Name {
-		case "merge":
-			for _, arg := range expr.Args {
-				if traversal, ok := arg.(*hclsyntax.ScopeTraversalExpr); ok {
-					// If the argument is a valid local variable invocation, then
-					// evaluate the value and get the tag key.
-					if localVarName, ok := r.extractLocalVarName(traversal); ok {
-						localVarTagsKey, err := r.evaluateLocalVarTagsKey(runner, localVarName)
-						if err != nil {
-							return nil, err
-						}
-						tagKeys = slices.Concat(tagKeys, localVarTagsKey)
-					}
-				} else {
-					// Otherwise, evaluate and extract keys.
-					if err := runner.EvaluateExpr(arg, func(val cty.Value) error {
-						tagKeys = slices.Concat(tagKeys, r.getTagsKey(val))
-						return nil
-					}, nil); err != nil {
-						return nil, err
-					}
-				}
-			}
-		case "concat":
-			for _, arg := range expr.Args {
-				if traversal, ok := arg.(*hclsyntax.ScopeTraversalExpr); ok {
-					// If the argument is a valid local variable invocation, then
-					// evaluate the value and get the tag key.
-					if localVarName, ok := r.extractLocalVarName(traversal); ok {
-						localVarTagsKey, err := r.evaluateLocalVarTagsKey(runner, localVarName)
-						if err != nil {
-							return nil, err
-						}
-						tagKeys = slices.Concat(tagKeys, localVarTagsKey)
-					}
-				} else {
-					// Otherwise, evaluate and extract keys.
-					if err := runner.EvaluateExpr(arg, func(val cty.Value) error {
-						tagKeys = slices.Concat(tagKeys, r.getTagsKey(val))
-						return nil
-					}, nil); err != nil {
-						keys, ok := r.handleEvaluateTupleError(err, arg.(*hclsyntax.TupleConsExpr))
-						if !ok {
-							return nil, err
-						} else {
-							tagKeys = slices.Concat(tagKeys, keys)
-						}
-					}
-				}
+		if tagAttr.lowercase {
+			if err := r.checkLowercaseTags(runner, tagAttr, resource, tagsRange, tags); err != nil {
+				return err
 			}
 		}
 
-	// Direct use of local variable on tags
-	// E.g. tags = local.tags
-	case *hclsyntax.ScopeTraversalExpr:
-		if localVarName, ok := r.extractLocalVarName(expr); ok {
-			localVarTagsKey, err := r.evaluateLocalVarTagsKey(runner, localVarName)
-			if err != nil {
-				return nil, err
-			}
-			tagKeys = slices.Concat(tagKeys, localVarTagsKey)
+		if err := r.checkTagValues(runner, config, resource, tagsRange, tags); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-	// When it's actual list values in tags field.
-	case *hclsyntax.TupleConsExpr:
-		if err := runner.EvaluateExpr(expr, func(val cty.Value) error {
-			tagKeys = slices.Concat(tagKeys, r.getTagsKey(val))
-			return nil
-		}, nil); err != nil {
-			keys, ok := r.handleEvaluateTupleError(err, expr)
-			if !ok {
-				return nil, err
-			} else {
-				tagKeys = slices.Concat(tagKeys, keys)
-			}
+// checkTagValues checks the values of the tags restricted by `tag_values`. A tag set
+// more than once takes the last value like merge(). Unknown values and sensitive values,
+// which must not be printed in issues, are skipped.
+func (r *TerraformRequiredTags) checkTagValues(runner tflint.Runner, config *terraformRequiredTagsConfig, resource *hclext.Block, tagsRange hcl.Range, tags []resourceTag) error {
+	var keys []string
+	values := map[string]cty.Value{}
+	for _, tag := range tags {
+		if _, exists := config.tagValues[tag.key]; !exists {
+			continue
 		}
-
-	// When it's actual object values in tags field.
-	case *hclsyntax.ObjectConsExpr:
-		if err := runner.EvaluateExpr(expr, func(val cty.Value) error {
-			tagKeys = slices.Concat(tagKeys, r.getTagsKey(val))
-			return nil
-		}, nil); err != nil {
-			return nil, err
+		if _, exists := values[tag.key]; !exists {
+			keys = append(keys, tag.key)
 		}
-
-	// Do nothing if unknown type.
-	default:
+		values[tag.key] = tag.value
 	}
-	return tagKeys, nil
-}
 
-// Extract the traversal expression to get the variable name, return false if it
-// is not an valid local variable invocation.
-// For example, a valid traversal expression to invoke local variable would be
-// 'local.my_tags'.
-func (r *TerraformRequiredTags) extractLocalVarName(traversal *hclsyntax.ScopeTraversalExpr) (string, bool) {
-	if len(traversal.Traversal) >= 2 {
-		rootName := traversal.Traversal[0].(hcl.TraverseRoot).Name
-		if rootName == "local" {
-			return traversal.Traversal[1].(hcl.TraverseAttr).Name, true
+	for _, key := range keys {
+		value, err := convert.Convert(values[key], cty.String)
+		if err != nil || !value.IsKnown() || value.IsNull() || value.IsMarked() {
+			continue
+		}
+
+		if violation := config.tagValues[key].violation(value.AsString()); violation != "" {
+			if err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("resource '%s.%s' has tag '%s' with value '%s', %s", resource.Labels[0], resource.Labels[1], key, value.AsString(), violation),
+				tagsRange,
+			); err != nil {
+				return err
+			}
 		}
 	}
-	return "", false
+	return nil
 }
 
-func (r *TerraformRequiredTags) evaluateLocalVarTagsKey(runner tflint.Runner, localVarName string) ([]string, error) {
-	locals, err := runner.GetModuleContent(&hclext.BodySchema{
+// providerDefaultTags returns the tags in `default_tags` of the provider blocks, by the
+// provider name such as "aws", or the name and the alias such as "aws.west".
+func (r *TerraformRequiredTags) providerDefaultTags(runner tflint.Runner, resolver *tagResolver) (map[string]*resourceTagSet, error) {
+	providers, err := runner.GetModuleContent(&hclext.BodySchema{
 		Blocks: []hclext.BlockSchema{
 			{
-				Type: "locals",
+				Type:       "provider",
+				LabelNames: []string{"name"},
 				Body: &hclext.BodySchema{
 					Attributes: []hclext.AttributeSchema{
-						{Name: localVarName},
+						{Name: "alias"},
+					},
+					Blocks: []hclext.BlockSchema{
+						{
+							Type: "default_tags",
+							Body: &hclext.BodySchema{
+								Attributes: []hclext.AttributeSchema{
+									{Name: "tags"},
+								},
+							},
+						},
 					},
 				},
 			},
 		},
-	}, nil)
+	}, &tflint.GetModuleContentOption{ExpandMode: tflint.ExpandModeNone})
 	if err != nil {
 		return nil, err
 	}
 
-	var localTagKeys []string
-	for _, block := range locals.Blocks {
-		if localVarAttr, ok := block.Body.Attributes[localVarName]; ok {
-			// Because there might be function call like merge() and concat() in
-			// local variables, or even using another local variable, so it will
-			// requires to perform a deep traverse into the nested local variable.
-			tagKeys, err := r.traverseSearchExpr(runner, localVarAttr.Expr)
-			if err != nil {
+	defaultTags := map[string]*resourceTagSet{}
+	for _, provider := range providers.Blocks {
+		name := provider.Labels[0]
+		if aliasAttr, exists := provider.Body.Attributes["alias"]; exists {
+			var alias string
+			if err := runner.EvaluateExpr(aliasAttr.Expr, &alias, nil); err != nil {
 				return nil, err
 			}
-			localTagKeys = slices.Concat(localTagKeys, tagKeys)
+			name = fmt.Sprintf("%s.%s", name, alias)
+		}
+
+		for _, block := range provider.Body.Blocks.OfType("default_tags") {
+			tagsAttr, exists := block.Body.Attributes["tags"]
+			if !exists {
+				continue
+			}
+			tags, resolved := resolver.traverseSearchExpr(tagsAttr.Expr)
+			defaultTags[name] = &resourceTagSet{tags: tags, rng: tagsAttr.Expr.Range(), resolved: resolved}
 		}
 	}
-	return localTagKeys, nil
+	return defaultTags, nil
 }
 
-func (r *TerraformRequiredTags) getTagsKey(val cty.Value) []string {
-	if val.IsKnown() && !val.IsNull() && val.CanIterateElements() {
-		var localTagKeys []string
-		if val.Type().IsObjectType() {
-			// If tags is object value
-			for it := val.ElementIterator(); it.Next(); {
-				k, _ := it.Element()
-				localTagKeys = append(localTagKeys, k.AsString())
-			}
-		} else if val.Type().IsTupleType() {
-			// If tags is list value, used in Openstack provider like compute_instance_v2.
-			for it := val.ElementIterator(); it.Next(); {
-				_, v := it.Element()
-				localTagKeys = append(localTagKeys, r.splitTagKeyString(v))
+// resourceProvider returns the provider configuration used by the resource, which is
+// set in the `provider` meta-argument such as "aws.west", or implied by the resource type.
+func (r *TerraformRequiredTags) resourceProvider(resource *hclext.Block) string {
+	if providerAttr, exists := resource.Body.Attributes["provider"]; exists {
+		if traversal, diags := hcl.AbsTraversalForExpr(providerAttr.Expr); !diags.HasErrors() {
+			var names []string
+			for _, step := range traversal {
+				switch step := step.(type) {
+				case hcl.TraverseRoot:
+					names = append(names, step.Name)
+				case hcl.TraverseAttr:
+					names = append(names, step.Name)
+				}
 			}
+			return strings.Join(names, ".")
 		}
-		return localTagKeys
 	}
-	return []string{}
+	name, _, _ := strings.Cut(resource.Labels[0], "_")
+	return name
 }
 
-// Handle error when trying to evaluate tuple values by manually Manually parsing
-// the list when the error is unknown variable or null value.
-// This might happen because TFLint do not know the actual value when using locals,
-// variable or output from other resources in the string.
-func (r *TerraformRequiredTags) handleEvaluateTupleError(err error, expr *hclsyntax.TupleConsExpr) ([]string, bool) {
-	var tagsKey []string
-	if strings.Contains(err.Error(), "Unknown variable") || strings.Contains(err.Error(), "Attempt to get attribute from null value") || strings.Contains(err.Error(), "This object does not have an attribute named") {
-		for _, e := range expr.Exprs {
-			if tmplExpr, ok := e.(*hclsyntax.TemplateExpr); ok {
-				for _, part := range tmplExpr.Parts {
-					if partExpr, ok := part.(*hclsyntax.LiteralValueExpr); ok {
-						tagsKey = append(tagsKey, r.splitTagKeyString(partExpr.Val))
-					}
+// resourceTags returns the tags of the resource set in the tag attribute. It returns nil
+// if the resource does not set the tag attribute.
+func (r *TerraformRequiredTags) resourceTags(runner tflint.Runner, resolver *tagResolver, tagAttr *tagAttribute, resource *hclext.Block) (*resourceTagSet, error) {
+	body := resource.Body
+	for _, blockType := range tagAttr.path[:len(tagAttr.path)-1] {
+		blocks := body.Blocks.OfType(blockType)
+		if len(blocks) == 0 {
+			return nil, nil
+		}
+		body = blocks[0].Body
+	}
+	name := tagAttr.path[len(tagAttr.path)-1]
+
+	if tagAttr.format == tagFormatBlock {
+		blocks := body.Blocks.OfType(name)
+		if len(blocks) == 0 {
+			return nil, nil
+		}
+
+		tagSet := &resourceTagSet{rng: blocks[0].DefRange, resolved: true}
+		for _, block := range blocks {
+			keyAttr, exists := block.Body.Attributes["key"]
+			if !exists {
+				continue
+			}
+			tag := resourceTag{value: cty.UnknownVal(cty.String)}
+			if err := runner.EvaluateExpr(keyAttr.Expr, func(key string) error {
+				tag.key = key
+				return nil
+			}, nil); err != nil {
+				return nil, err
+			}
+			if tag.key == "" {
+				tagSet.resolved = false
+				continue
+			}
+			if valueAttr, exists := block.Body.Attributes["value"]; exists {
+				if err := runner.EvaluateExpr(valueAttr.Expr, func(value cty.Value) error {
+					tag.value = value
+					return nil
+				}, nil); err != nil {
+					return nil, err
 				}
 			}
+			tagSet.tags = append(tagSet.tags, tag)
 		}
-		return tagsKey, true
-	} else {
-		return nil, false
+		return tagSet, nil
+	}
+
+	attr, exists := body.Attributes[name]
+	if !exists {
+		return nil, nil
 	}
+	tags, resolved := resolver.traverseSearchExpr(attr.Expr)
+	// A map is not a list of "key:value" strings and vice versa, so tags of the
+	// other format are not counted when the format is set.
+	switch tagAttr.format {
+	case tagFormatMap:
+		tags = slices.DeleteFunc(tags, func(tag resourceTag) bool { return tag.list })
+	case tagFormatList:
+		tags = slices.DeleteFunc(tags, func(tag resourceTag) bool { return !tag.list })
+	}
+	return &resourceTagSet{tags: tags, rng: attr.Expr.Range(), resolved: resolved}, nil
 }
 
-// Split a single tag key in string with delimiter ':'.
-func (r *TerraformRequiredTags) splitTagKeyString(val cty.Value) string {
-	// If the value is unknown, AsString() will throw panic errors.
-	if !val.IsWhollyKnown() {
-		return strings.Split(val.Range().StringPrefix(), ":")[0]
-	} else {
-		return strings.Split(val.AsString(), ":")[0]
+// checkLowercaseTags checks whether the keys and values of the tags have no uppercase
+// letters, such as labels of Google Cloud resources. Sensitive values are skipped, so
+// that they are not printed in issues.
+func (r *TerraformRequiredTags) checkLowercaseTags(runner tflint.Runner, tagAttr *tagAttribute, resource *hclext.Block, tagsRange hcl.Range, tags []resourceTag) error {
+	for _, tag := range tags {
+		var message string
+		if tag.key != strings.ToLower(tag.key) {
+			message = fmt.Sprintf("resource '%s.%s' has tag '%s' with uppercase letters, which are not allowed in '%s'", resource.Labels[0], resource.Labels[1], tag.key, tagAttr.name())
+		} else if value, err := convert.Convert(tag.value, cty.String); err == nil && value.IsKnown() && !value.IsNull() && !value.IsMarked() && value.AsString() != strings.ToLower(value.AsString()) {
+			message = fmt.Sprintf("resource '%s.%s' has tag '%s' with value '%s' with uppercase letters, which are not allowed in '%s'", resource.Labels[0], resource.Labels[1], tag.key, value.AsString(), tagAttr.name())
+		}
+		if message == "" {
+			continue
+		}
+
+		if err := runner.EmitIssue(r, message, tagsRange); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// Function to determine whether resource has `aws_` prefix
+func (r *TerraformRequiredTags) isAwsResource(resource string) bool {
+	return strings.HasPrefix(resource, "aws_")
 }
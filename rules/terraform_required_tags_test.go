@@ -1,10 +1,16 @@
 package rules
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint-plugin-sdk/helper"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func Test_TerraformRequiredTags(t *testing.T) {
@@ -561,6 +567,1524 @@ resource "foo" "my_resource" {
 	}
 }
 
+func Test_TerraformRequiredTags_TagValues(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+	}{
+		{
+			Name: "resource with a sensitive tag value.",
+			Content: `
+variable "env" {
+  default   = "Prod"
+  sensitive = true
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = {
+    env = var.env
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with allowed tag values.",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = {
+    env   = "prod"
+    brand = "myklst"
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with tag values not allowed.",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = {
+    env   = "production"
+    brand = "MyKLST"
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' has tag 'env' with value 'production', which is not one of ['prod', 'staging', 'dev']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 10},
+						End:      hcl.Pos{Line: 6, Column: 4},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' has tag 'brand' with value 'MyKLST', which does not match '^[a-z]{2,8}$'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 10},
+						End:      hcl.Pos{Line: 6, Column: 4},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with tag value not allowed in local variable.",
+			Content: `
+locals {
+  tags = {
+    env = "qa"
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = merge(local.tags, {
+    brand = "myklst"
+  })
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' has tag 'env' with value 'qa', which is not one of ['prod', 'staging', 'dev']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 9, Column: 10},
+						End:      hcl.Pos{Line: 11, Column: 5},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource overriding tag value not allowed in local variable.",
+			Content: `
+locals {
+  tags = {
+    env = "qa"
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = merge(local.tags, {
+    env = "dev"
+  })
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with tag value not allowed (list of string).",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = ["env:production", "brand:myklst"]
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' has tag 'env' with value 'production', which is not one of ['prod', 'staging', 'dev']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 10},
+						End:      hcl.Pos{Line: 3, Column: 44},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with unknown tag value (list of string).",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = ["env:${local.env}", "brand:myklst"]
+}
+`,
+			Expected: helper.Issues{},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf":     test.Content,
+				".tflint.hcl": testTerraformRequiredTagsValuesConfig,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformRequiredTags_TagAttributes(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+	}{
+		{
+			Name: "google resource with a sensitive label value.",
+			Content: `
+variable "env" {
+  default   = "Prod"
+  sensitive = true
+}
+
+resource "google_storage_bucket" "my_bucket" {
+  labels = {
+    env = var.env
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "google resource with the correct required labels.",
+			Content: `
+resource "google_storage_bucket" "my_bucket" {
+  tags = ["web"]
+
+  labels = {
+    env = "prod"
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "google resource with the missing required labels.",
+			Content: `
+resource "google_compute_instance" "my_instance" {
+  tags = ["env:prod"]
+
+  labels = {
+    brand = "myklst"
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'google_compute_instance.my_instance' is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 12},
+						End:      hcl.Pos{Line: 7, Column: 4},
+					},
+				},
+			},
+		},
+		{
+			Name: "google resource with uppercase letters in labels.",
+			Content: `
+resource "google_storage_bucket" "my_bucket" {
+  labels = {
+    env   = "Prod"
+    Brand = "myklst"
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'google_storage_bucket.my_bucket' has tag 'Brand' with uppercase letters, which are not allowed in 'labels'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 6, Column: 4},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'google_storage_bucket.my_bucket' has tag 'env' with value 'Prod' with uppercase letters, which are not allowed in 'labels'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12},
+						End:      hcl.Pos{Line: 6, Column: 4},
+					},
+				},
+			},
+		},
+		{
+			Name: "google resource without labels.",
+			Content: `
+resource "google_compute_instance" "my_instance" {
+  tags = ["web"]
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'google_compute_instance.my_instance' does not set 'labels' and is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 49},
+					},
+				},
+			},
+		},
+		{
+			Name: "kubernetes resource with the correct required labels in metadata.",
+			Content: `
+resource "kubernetes_namespace" "my_namespace" {
+  metadata {
+    name = "my-namespace"
+
+    labels = {
+      env = "prod"
+    }
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "kubernetes resource with the missing required labels in metadata.",
+			Content: `
+resource "kubernetes_namespace" "my_namespace" {
+  metadata {
+    name = "my-namespace"
+
+    labels = {
+      brand = "myklst"
+    }
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'kubernetes_namespace.my_namespace' is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 6, Column: 14},
+						End:      hcl.Pos{Line: 8, Column: 6},
+					},
+				},
+			},
+		},
+		{
+			Name: "aws resource with the correct required tags in blocks.",
+			Content: `
+resource "aws_autoscaling_group" "my_asg" {
+  tag {
+    key                 = "Name"
+    value               = "my-asg"
+    propagate_at_launch = true
+  }
+
+  tag {
+    key                 = "env"
+    value               = "prod"
+    propagate_at_launch = true
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "aws resource with the missing required tags in blocks.",
+			Content: `
+resource "aws_autoscaling_group" "my_asg" {
+  tag {
+    key                 = "brand"
+    value               = "myklst"
+    propagate_at_launch = true
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'aws_autoscaling_group.my_asg' is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 3, Column: 6},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "aws resources must have 'Name' tag: 'aws_autoscaling_group.my_asg'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 3, Column: 6},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with tags in a map instead of the list format.",
+			Content: `
+resource "openstack_compute_instance_v2" "my_instance" {
+  tags = {
+    env = "prod"
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'openstack_compute_instance_v2.my_instance' is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 10},
+						End:      hcl.Pos{Line: 5, Column: 4},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf":     test.Content,
+				".tflint.hcl": testTerraformRequiredTagsAttributesConfig,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformRequiredTags_DefaultTags(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+	}{
+		{
+			Name: "aws resource with required tags in provider default_tags.",
+			Content: `
+locals {
+  tags = {
+    env   = "prod"
+    brand = "myklst"
+  }
+}
+
+provider "aws" {
+  default_tags {
+    tags = local.tags
+  }
+}
+
+resource "aws_instance" "my_instance" {
+  tags = {
+    Name = "my-instance"
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "aws resource with required tags in aliased provider default_tags.",
+			Content: `
+provider "aws" {
+  alias = "west"
+
+  default_tags {
+    tags = {
+      env   = "prod"
+      brand = "myklst"
+    }
+  }
+}
+
+resource "aws_instance" "my_instance" {
+  provider = aws.west
+
+  tags = {
+    Name = "my-instance"
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "aws resource using provider alias without default_tags.",
+			Content: `
+provider "aws" {
+  default_tags {
+    tags = {
+      env   = "prod"
+      brand = "myklst"
+    }
+  }
+}
+
+provider "aws" {
+  alias = "west"
+}
+
+resource "aws_instance" "my_instance" {
+  provider = aws.west
+
+  tags = {
+    Name = "my-instance"
+    env  = "prod"
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'aws_instance.my_instance' is missing required tags: ['brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 18, Column: 10},
+						End:      hcl.Pos{Line: 21, Column: 4},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf": test.Content,
+				".tflint.hcl": `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env", "brand"]
+}`,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformRequiredTags_UntaggedResources(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+	}{
+		{
+			Name: "taggable aws resource without tags.",
+			Content: `
+resource "aws_s3_bucket" "my_bucket" {
+  bucket = "my-bucket"
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'aws_s3_bucket.my_bucket' does not set 'tags' and is missing required tags: ['env', 'brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 37},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "aws resources must have 'Name' tag: 'aws_s3_bucket.my_bucket'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 37},
+					},
+				},
+			},
+		},
+		{
+			Name: "taggable aws resource without tags but with provider default_tags.",
+			Content: `
+provider "aws" {
+  default_tags {
+    tags = {
+      Name  = "my-bucket"
+      env   = "prod"
+      brand = "myklst"
+    }
+  }
+}
+
+resource "aws_s3_bucket" "my_bucket" {
+  bucket = "my-bucket"
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "taggable azurerm resource without tags.",
+			Content: `
+resource "azurerm_resource_group" "my_group" {
+  name     = "my-group"
+  location = "eastasia"
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'azurerm_resource_group.my_group' does not set 'tags' and is missing required tags: ['env', 'brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 45},
+					},
+				},
+			},
+		},
+		{
+			Name: "aws resource which does not support tags.",
+			Content: `
+resource "aws_iam_role_policy" "my_policy" {
+  role = "my-role"
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "aws_autoscaling_group without tag blocks.",
+			Content: `
+resource "aws_autoscaling_group" "my_asg" {
+  max_size = 1
+}
+`,
+			Expected: helper.Issues{},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf": test.Content,
+				".tflint.hcl": `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env", "brand"]
+}`,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformRequiredTags_ProvidersSchemaFile(t *testing.T) {
+	// A schema with tags on aws_iam_role_policy but not on aws_s3_bucket, so that the
+	// schema is preferred to the embedded list.
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	schema := `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {
+      "resource_schemas": {
+        "aws_iam_role_policy": {
+          "block": {"attributes": {"role": {"type": "string"}, "tags": {"type": ["map", "string"]}}}
+        },
+        "aws_s3_bucket": {
+          "block": {"attributes": {"bucket": {"type": "string"}}}
+        }
+      }
+    },
+    "registry.terraform.io/hashicorp/kubernetes": {
+      "resource_schemas": {
+        "kubernetes_namespace": {
+          "block": {
+            "block_types": {
+              "metadata": {"block": {"attributes": {"labels": {"type": ["map", "string"]}}}}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+	if err := os.WriteFile(schemaFile, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+	}{
+		{
+			Name: "resource with tags in the schema.",
+			Content: `
+resource "aws_iam_role_policy" "my_policy" {
+  role = "my-role"
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'aws_iam_role_policy.my_policy' does not set 'tags' and is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 43},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "aws resources must have 'Name' tag: 'aws_iam_role_policy.my_policy'",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 43},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource without tags in the schema.",
+			Content: `
+resource "aws_s3_bucket" "my_bucket" {
+  bucket = "my-bucket"
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with labels in a nested block of the schema.",
+			Content: `
+resource "kubernetes_namespace" "my_namespace" {
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'kubernetes_namespace.my_namespace' does not set 'metadata.labels' and is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 47},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource not in the schema falls back to the embedded list.",
+			Content: `
+resource "google_storage_bucket" "my_bucket" {
+  name = "my-bucket"
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'google_storage_bucket.my_bucket' does not set 'labels' and is missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 45},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf": test.Content,
+				".tflint.hcl": fmt.Sprintf(`
+rule "terraform_required_tags" {
+  enabled               = true
+  tags                  = ["env"]
+  providers_schema_file = %q
+}`, schemaFile),
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformRequiredTags_Expressions(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+	}{
+		{
+			Name: "resource with tags in a variable of list type.",
+			Content: `
+variable "tags" {
+  type    = list(string)
+  default = ["env:prod", "brand:myklst"]
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = var.tags
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with a sensitive variable in a list tag.",
+			Content: `
+variable "env" {
+  default   = "prod"
+  sensitive = true
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = ["env:${var.env}", "brand:x"]
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with sensitive list tags.",
+			Content: `
+variable "tags" {
+  default   = ["env:prod", "brand:x"]
+  sensitive = true
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = var.tags
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with sensitive object tags.",
+			Content: `
+variable "tags" {
+  default = {
+    env   = "prod"
+    brand = "myklst"
+  }
+  sensitive = true
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = var.tags
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with a sensitive tag key.",
+			Content: `
+variable "key" {
+  default   = "env"
+  sensitive = true
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = {
+    (var.key) = "prod"
+    brand     = "myklst"
+  }
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "could not resolve tags statically for resource 'my_resource.my_resource_name', which may be missing required tags: ['env']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 8, Column: 10},
+						End:      hcl.Pos{Line: 11, Column: 4},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with the required tags in both branches of a conditional.",
+			Content: `
+variable "enabled" {
+  default = true
+}
+
+locals {
+  tags = {
+    env   = "prod"
+    brand = "myklst"
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = var.enabled ? merge(local.tags, { project = "my-project" }) : local.tags
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with a required tag in only one branch of a conditional.",
+			Content: `
+variable "enabled" {
+  default = true
+}
+
+locals {
+  tags = {
+    env = "prod"
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = var.enabled ? merge(local.tags, { brand = "myklst" }) : local.tags
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' is missing required tags: ['brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 13, Column: 10},
+						End:      hcl.Pos{Line: 13, Column: 76},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with the required tags in tomap() and a for expression.",
+			Content: `
+variable "extra" {
+  default = {
+    brand = "myklst"
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = tomap(merge({ for k, v in var.extra : k => v }, { env = "prod" }))
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with the required tags in an attribute of a local variable.",
+			Content: `
+locals {
+  base = {
+    tags = {
+      env   = "prod"
+      brand = "myklst"
+    }
+  }
+  common = local.base
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = local.common.tags
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with the missing required tags in an attribute of a local variable.",
+			Content: `
+locals {
+  common = {
+    tags = {
+      env = "prod"
+    }
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = local.common["tags"]
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' is missing required tags: ['brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 11, Column: 10},
+						End:      hcl.Pos{Line: 11, Column: 30},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with the required tags in all values of lookup().",
+			Content: `
+variable "env" {
+  default = "prod"
+}
+
+locals {
+  tags_by_env = {
+    prod = {
+      env   = "prod"
+      brand = "myklst"
+    }
+    dev = {
+      env   = "dev"
+      brand = "myklst"
+    }
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = lookup(local.tags_by_env, var.env, { env = "prod", brand = "myklst" })
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with the missing required tags in lookup() of the known key.",
+			Content: `
+locals {
+  tags_by_env = {
+    prod = {
+      env   = "prod"
+      brand = "myklst"
+    }
+    dev = {
+      env = "dev"
+    }
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = lookup(local.tags_by_env, "dev")
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' is missing required tags: ['brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 15, Column: 10},
+						End:      hcl.Pos{Line: 15, Column: 42},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with tags which cannot be resolved statically.",
+			Content: `
+locals {
+  tags = {
+    env = "prod"
+  }
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = merge(local.tags, local.extra_tags)
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "could not resolve tags statically for resource 'my_resource.my_resource_name', which may be missing required tags: ['brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 9, Column: 10},
+						End:      hcl.Pos{Line: 9, Column: 45},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with the required tags, and tags which cannot be resolved statically.",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = merge(local.extra_tags, {
+    env   = "prod"
+    brand = "myklst"
+  })
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource concating a list which cannot be resolved statically.",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = concat(["env:prod", "brand:myklst"], split(",", local.extra_tags))
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with tag values referring to other resources.",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = {
+    env   = aws_vpc.main.tags["env"]
+    brand = "myklst"
+  }
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with tag values referring to attributes of a null value (list of string).",
+			Content: `
+variable "settings" {
+  default = null
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = ["env:${var.settings.env}", "brand:myklst"]
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with tag keys which cannot be resolved statically (list of string).",
+			Content: `
+resource "my_resource" "my_resource_name" {
+  tags = ["env:prod", "${aws_vpc.main.id}:myklst"]
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "could not resolve tags statically for resource 'my_resource.my_resource_name', which may be missing required tags: ['brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 10},
+						End:      hcl.Pos{Line: 3, Column: 51},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf": test.Content,
+				".tflint.hcl": `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env", "brand"]
+}`,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+func Test_TerraformRequiredTags_LocalCycles(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected helper.Issues
+	}{
+		{
+			Name: "local variables referring to each other in a cycle.",
+			Content: `
+locals {
+  tags        = merge(local.common_tags, { env = "prod" })
+  common_tags = merge(local.tags, { brand = "myklst" })
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = local.tags
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "local variables ['common_tags', 'tags'] refer to each other in a cycle",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 3},
+						End:      hcl.Pos{Line: 4, Column: 56},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "could not resolve tags statically for resource 'my_resource.my_resource_name', which may be missing required tags: ['env', 'brand']",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 8, Column: 10},
+						End:      hcl.Pos{Line: 8, Column: 20},
+					},
+				},
+			},
+		},
+		{
+			Name: "local variable referring to itself.",
+			Content: `
+locals {
+  tags = merge(local.tags, { env = "prod" })
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = merge(local.tags, { env = "prod", brand = "myklst" })
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "local variables ['tags'] refer to each other in a cycle",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 3, Column: 45},
+					},
+				},
+			},
+		},
+		{
+			Name: "local variables referring to each other without a cycle.",
+			Content: `
+locals {
+  base   = { env = "prod" }
+  common = merge(local.base, { brand = "myklst" })
+  tags   = merge(local.base, local.common)
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = local.tags
+}
+`,
+			Expected: helper.Issues{},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf": test.Content,
+				".tflint.hcl": `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env", "brand"]
+}`,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+// unknownVariablesRunner evaluates the expressions referring to variables as unknown
+// values, as tflint does for variables without a default.
+type unknownVariablesRunner struct {
+	*helper.Runner
+}
+
+func (r *unknownVariablesRunner) EvaluateExpr(expr hcl.Expression, target any, opts *tflint.EvaluateExprOption) error {
+	callback, ok := target.(func(cty.Value) error)
+	if !ok || len(expr.Variables()) == 0 {
+		return r.Runner.EvaluateExpr(expr, target, opts)
+	}
+	return callback(cty.DynamicVal)
+}
+
+func Test_TerraformRequiredTags_UnknownVariables(t *testing.T) {
+	runner := &unknownVariablesRunner{
+		Runner: helper.TestRunner(t, map[string]string{
+			"main.tf": `
+variable "tags" {
+  type = map(string)
+}
+
+resource "my_resource" "my_resource_name" {
+  tags = var.tags
+}
+`,
+			".tflint.hcl": `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env", "brand"]
+}`,
+		}),
+	}
+
+	if err := NewTerraformRequiredTags().Check(runner); err != nil {
+		t.Fatalf("Unexpected error occurred: %s", err)
+	}
+
+	helper.AssertIssues(t, helper.Issues{
+		{
+			Rule:    NewTerraformRequiredTags(),
+			Message: "could not resolve tags statically for resource 'my_resource.my_resource_name', which may be missing required tags: ['env', 'brand']",
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 7, Column: 10},
+				End:      hcl.Pos{Line: 7, Column: 18},
+			},
+		},
+	}, runner.Issues)
+}
+
+// localsCountingRunner counts the calls of GetModuleContent fetching local variables.
+type localsCountingRunner struct {
+	*helper.Runner
+	localsCalls int
+}
+
+func (r *localsCountingRunner) GetModuleContent(schema *hclext.BodySchema, opts *tflint.GetModuleContentOption) (*hclext.BodyContent, error) {
+	for _, block := range schema.Blocks {
+		if block.Type == "locals" {
+			r.localsCalls++
+		}
+	}
+	return r.Runner.GetModuleContent(schema, opts)
+}
+
+func Test_TerraformRequiredTags_LocalsFetchedOnce(t *testing.T) {
+	runner := &localsCountingRunner{
+		Runner: helper.TestRunner(t, map[string]string{
+			"main.tf": `
+locals {
+  base = { env = "prod" }
+  tags = merge(local.base, { brand = "myklst" })
+}
+
+resource "my_resource" "first" {
+  tags = local.tags
+}
+
+resource "my_resource" "second" {
+  tags = merge(local.tags, { project = "my-project" })
+}
+
+resource "my_resource" "third" {
+  tags = ["env:${local.base.env}", "brand:myklst"]
+}
+`,
+			".tflint.hcl": `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env", "brand"]
+}`,
+		}),
+	}
+
+	if err := NewTerraformRequiredTags().Check(runner); err != nil {
+		t.Fatalf("Unexpected error occurred: %s", err)
+	}
+
+	helper.AssertIssues(t, helper.Issues{}, runner.Issues)
+	if runner.localsCalls != 1 {
+		t.Errorf("Expected local variables to be fetched once, but fetched %d times", runner.localsCalls)
+	}
+}
+
+func Test_TerraformRequiredTags_JSON(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Config   string
+		Expected helper.Issues
+	}{
+		{
+			Name: "resource with the correct required tags referring to variable.",
+			Content: `{
+  "resource": {
+    "my_resource": {
+      "my_resource_name": {
+        "name": "test",
+        "tags": {
+          "my_required_tag": "${var.env}"
+        }
+      }
+    }
+  }
+}`,
+			Config:   testTerraformRequiredTagsConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "aws resource with required tags in aliased provider default_tags.",
+			Content: `{
+  "provider": {
+    "aws": {
+      "alias": "west",
+      "default_tags": {
+        "tags": {
+          "my_required_tag": "my_tag"
+        }
+      }
+    }
+  },
+  "resource": {
+    "aws_instance": {
+      "my_instance": {
+        "provider": "aws.west",
+        "tags": {
+          "Name": "my-instance"
+        }
+      }
+    }
+  }
+}`,
+			Config:   testTerraformRequiredTagsConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with tag value not allowed.",
+			Content: `{
+  "resource": {
+    "my_resource": {
+      "my_resource_name": {
+        "tags": {
+          "env": "production",
+          "brand": "${var.brand}"
+        }
+      }
+    }
+  }
+}`,
+			Config: testTerraformRequiredTagsValuesConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' has tag 'env' with value 'production', which is not one of ['prod', 'staging', 'dev']",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 5, Column: 17},
+						End:      hcl.Pos{Line: 8, Column: 10},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource with the missing required tags.",
+			Content: `{
+  "resource": {
+    "my_resource": {
+      "my_resource_name": {
+        "name": "test",
+        "tags": {
+          "my_incorrect_tag": "my_tag"
+        }
+      }
+    }
+  }
+}`,
+			Config: testTerraformRequiredTagsConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'my_resource.my_resource_name' is missing required tags: ['my_required_tag']",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 6, Column: 17},
+						End:      hcl.Pos{Line: 8, Column: 10},
+					},
+				},
+			},
+		},
+		{
+			Name: "resource using local variable as tags, and local variable `tags` with the correct required tags.",
+			Content: `{
+  "locals": {
+    "tags": {
+      "my_required_tag": "my_tag"
+    }
+  },
+  "resource": {
+    "my_resource": {
+      "my_resource_name": {
+        "tags": "${local.tags}"
+      }
+    }
+  }
+}`,
+			Config:   testTerraformRequiredTagsConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with correct required tags (list of string) referring to variable.",
+			Content: `{
+  "resource": {
+    "foo": {
+      "my_resource": {
+        "tags": ["my_required_tag:${var.env}"]
+      }
+    }
+  }
+}`,
+			Config:   testTerraformRequiredTagsConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "resource with missing required tags (list of string).",
+			Content: `{
+  "resource": {
+    "foo": {
+      "my_resource": {
+        "tags": ["my_incorrect_tag:dev"]
+      }
+    }
+  }
+}`,
+			Config: testTerraformRequiredTagsConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredTags(),
+					Message: "resource 'foo.my_resource' is missing required tags: ['my_required_tag']",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 5, Column: 17},
+						End:      hcl.Pos{Line: 5, Column: 41},
+					},
+				},
+			},
+		},
+	}
+
+	rule := NewTerraformRequiredTags()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf.json": test.Content,
+				".tflint.hcl":  test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
 const testTerraformRequiredTagsConfig = `
 rule "terraform_required_tags" {
   enabled            = true
@@ -569,3 +2093,35 @@ rule "terraform_required_tags" {
   excluded_resources = ["my_excluded_resource", "my_excluded_resource_v2.my_resource"]
 }
 `
+
+const testTerraformRequiredTagsValuesConfig = `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env"]
+
+  tag_values "env" {
+    values = ["prod", "staging", "dev"]
+  }
+
+  tag_values "brand" {
+    regex = "^[a-z]{2,8}$"
+  }
+}
+`
+
+const testTerraformRequiredTagsAttributesConfig = `
+rule "terraform_required_tags" {
+  enabled = true
+  tags    = ["env"]
+
+  tag_attribute "aws_autoscaling_group" {
+    path   = "tag"
+    format = "block"
+  }
+
+  tag_attribute "openstack_" {
+    path   = "tags"
+    format = "list"
+  }
+}
+`
@@ -1,14 +1,22 @@
 package rules
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/myklst/tflint-ruleset-myklst/project"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // TerraformRequiredVariables checks whether variables have a type checked
@@ -17,7 +25,141 @@ type TerraformRequiredVariables struct {
 }
 
 type terraformRequiredVariablesConfig struct {
-	RequiredVars []string `hclext:"required_vars,optional"`
+	RequiredVars         []string                              `hclext:"required_vars,optional"`
+	SensitiveVars        []string                              `hclext:"sensitive_vars,optional"`
+	SensitiveVarPatterns []string                              `hclext:"sensitive_var_patterns,optional"`
+	Variables            []terraformRequiredVariableConfig     `hclext:"variable,block"`
+	StubTemplate         *terraformRequiredVariablesStubConfig `hclext:"stub_template,block"`
+
+	contracts            map[string]requiredVariableContract
+	sensitiveVarPatterns []*regexp.Regexp
+}
+
+// terraformRequiredVariableConfig is the schema contract of a required variable.
+type terraformRequiredVariableConfig struct {
+	Name      string `hclext:"name,label"`
+	Type      string `hclext:"type,optional"`
+	Nullable  *bool  `hclext:"nullable,optional"`
+	Sensitive *bool  `hclext:"sensitive,optional"`
+	NoDefault bool   `hclext:"no_default,optional"`
+	// Description is only used in the stub of the missing variable.
+	Description string `hclext:"description,optional"`
+}
+
+// terraformRequiredVariablesStubConfig is the template of the stubs appended for the
+// missing variables by autofix.
+type terraformRequiredVariablesStubConfig struct {
+	Type        string `hclext:"type,optional"`
+	Description string `hclext:"description,optional"`
+	Sensitive   bool   `hclext:"sensitive,optional"`
+}
+
+// requiredVariableContract is the schema contract of a required variable with the
+// expected type parsed from the type expression.
+type requiredVariableContract struct {
+	terraformRequiredVariableConfig
+	// expectedType is cty.NilType if the type is not specified.
+	expectedType cty.Type
+}
+
+// validate sets the defaults of the config, and parses the schema contracts and patterns.
+func (config *terraformRequiredVariablesConfig) validate() error {
+	// Set default required variables if none are specified.
+	if len(config.RequiredVars) == 0 {
+		config.RequiredVars = []string{
+			"cloud_creds",
+			"module_info",
+			"module_tmpl",
+		}
+	}
+
+	// Set default sensitive variables if none are specified.
+	if len(config.SensitiveVars) == 0 {
+		config.SensitiveVars = []string{"cloud_creds"}
+	}
+
+	// Variables with schema contracts are also required, and the sensitive ones follow
+	// the same policy as sensitive_vars.
+	config.contracts = make(map[string]requiredVariableContract, len(config.Variables))
+	for _, variable := range config.Variables {
+		contract := requiredVariableContract{terraformRequiredVariableConfig: variable}
+		if variable.Type != "" {
+			var err error
+			if contract.expectedType, err = parseTypeConstraint(fmt.Sprintf("variable[%q].type", variable.Name), variable.Type); err != nil {
+				return err
+			}
+		}
+		config.contracts[variable.Name] = contract
+
+		if !slices.Contains(config.RequiredVars, variable.Name) {
+			config.RequiredVars = append(config.RequiredVars, variable.Name)
+		}
+		if variable.Sensitive != nil && *variable.Sensitive {
+			config.SensitiveVars = append(config.SensitiveVars, variable.Name)
+		}
+	}
+
+	// The type of the stub template is written into the stubs as is.
+	if config.StubTemplate != nil && config.StubTemplate.Type != "" {
+		if _, err := parseTypeConstraint("stub_template.type", config.StubTemplate.Type); err != nil {
+			return err
+		}
+	}
+
+	if _, err := compileRegexps("sensitive_var_patterns", config.SensitiveVarPatterns); err != nil {
+		return err
+	}
+	// Patterns must match the whole variable name.
+	config.sensitiveVarPatterns = make([]*regexp.Regexp, len(config.SensitiveVarPatterns))
+	for i, pattern := range config.SensitiveVarPatterns {
+		config.sensitiveVarPatterns[i] = regexp.MustCompile("^(?:" + pattern + ")$")
+	}
+	return nil
+}
+
+// parseTypeConstraint parses the type constraint of the key in the rule config. Optional
+// object attributes may have defaults, such as `optional(map(string), {})`.
+func parseTypeConstraint(key string, typ string) (cty.Type, error) {
+	// The position of the diagnostics is in the type string, not in the rule config.
+	typeExpr, diags := hclsyntax.ParseExpression([]byte(typ), ".tflint.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilType, configErrorf(key, "`%s`: %s; %s", typ, diags[0].Summary, diags[0].Detail)
+	}
+	ty, _, diags := typeexpr.TypeConstraintWithDefaults(typeExpr)
+	if diags.HasErrors() {
+		return cty.NilType, configErrorf(key, "`%s`: %s; %s", typ, diags[0].Summary, diags[0].Detail)
+	}
+	return ty, nil
+}
+
+// typeString returns the type expression of the type like typeexpr.TypeString, with the
+// optional object attributes in `optional()`.
+func typeString(ty cty.Type) string {
+	switch {
+	case ty.IsListType():
+		return fmt.Sprintf("list(%s)", typeString(ty.ElementType()))
+	case ty.IsSetType():
+		return fmt.Sprintf("set(%s)", typeString(ty.ElementType()))
+	case ty.IsMapType():
+		return fmt.Sprintf("map(%s)", typeString(ty.ElementType()))
+	case ty.IsTupleType():
+		elems := make([]string, len(ty.TupleElementTypes()))
+		for i, elemType := range ty.TupleElementTypes() {
+			elems[i] = typeString(elemType)
+		}
+		return fmt.Sprintf("tuple([%s])", strings.Join(elems, ","))
+	case ty.IsObjectType():
+		var attrs []string
+		for _, name := range slices.Sorted(maps.Keys(ty.AttributeTypes())) {
+			attr := typeString(ty.AttributeType(name))
+			if ty.AttributeOptional(name) {
+				attr = fmt.Sprintf("optional(%s)", attr)
+			}
+			attrs = append(attrs, fmt.Sprintf("%s=%s", name, attr))
+		}
+		return fmt.Sprintf("object({%s})", strings.Join(attrs, ","))
+	}
+	return typeexpr.TypeString(ty)
 }
 
 // NewTerraformRequiredVariables returns a new rule
@@ -49,18 +191,10 @@ func (r *TerraformRequiredVariables) Link() string {
 func (r *TerraformRequiredVariables) Check(runner tflint.Runner) error {
 	config := &terraformRequiredVariablesConfig{}
 
-	if err := runner.DecodeRuleConfig(r.Name(), config); err != nil {
+	if err := decodeRuleConfig(runner, r, config); err != nil {
 		return err
 	}
-
-	// Set default required variables if none are specified.
-	if len(config.RequiredVars) == 0 {
-		config.RequiredVars = []string{
-			"cloud_creds",
-			"module_info",
-			"module_tmpl",
-		}
-	}
+	contracts, sensitiveVarPatterns := config.contracts, config.sensitiveVarPatterns
 
 	variables, err := runner.GetModuleContent(&hclext.BodySchema{
 		Blocks: []hclext.BlockSchema{
@@ -72,6 +206,15 @@ func (r *TerraformRequiredVariables) Check(runner tflint.Runner) error {
 						{
 							Name: "sensitive",
 						},
+						{
+							Name: "default",
+						},
+						{
+							Name: "type",
+						},
+						{
+							Name: "nullable",
+						},
 					},
 				},
 			},
@@ -96,12 +239,30 @@ func (r *TerraformRequiredVariables) Check(runner tflint.Runner) error {
 	}
 
 	if len(missingVars) > 0 {
-		err := runner.EmitIssue(
+		filename, file, err := r.anchorFile(runner, variables)
+		if err != nil {
+			return err
+		}
+
+		// The issue is placed at the beginning of the file, and the stubs of the missing
+		// variables are appended to the end of the file.
+		err = runner.EmitIssueWithFix(
 			r,
 			fmt.Sprintf("required variable(s) not declared: %s", strings.Join(missingVars, ", ")),
 			hcl.Range{
-				Start: hcl.Pos{Line: 1, Column: 1},
-				End:   hcl.Pos{Line: 1, Column: 1},
+				Filename: filename,
+				Start:    hcl.InitialPos,
+				End:      hcl.InitialPos,
+			},
+			func(f tflint.Fixer) error {
+				if file == nil || isJSONSyntax(filename) {
+					return tflint.ErrFixNotSupported
+				}
+				stubs := r.variableStubs(missingVars, config, contracts, sensitiveVarPatterns)
+				if len(file.Bytes) > 0 && file.Bytes[len(file.Bytes)-1] != '\n' {
+					stubs = "\n" + stubs
+				}
+				return f.InsertTextAfter(file.Body.(*hclsyntax.Body).SrcRange, stubs)
 			},
 		)
 		if err != nil {
@@ -109,41 +270,111 @@ func (r *TerraformRequiredVariables) Check(runner tflint.Runner) error {
 		}
 	}
 
-	// Check for "cloud_creds" variable and its "sensitive" attribute
+	// Check for sensitive variables and their "sensitive" and "default" attributes.
 	for _, variable := range variables.Blocks {
-		if variable.Labels[0] == "cloud_creds" {
-			sensitiveAttr, sensitiveExist := variable.Body.Attributes["sensitive"]
-			// Check if "sensitive" attribute exist.
-			if sensitiveExist {
-				sensitiveValue, _ := sensitiveAttr.Expr.(*hclsyntax.LiteralValueExpr)
-				// Check if "sensitive" attribute is placed under variable definition.
-				if sensitiveAttr.Range.Start.Line != variable.DefRange.End.Line+1 {
-					err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("variable `%s` must place `sensitive = true` as first parameter after variable definition", variable.Labels[0]),
-						sensitiveAttr.Range,
-					)
-					if err != nil {
-						return err
-					}
+		if !r.isSensitiveVar(variable.Labels[0], config.SensitiveVars, sensitiveVarPatterns) {
+			continue
+		}
+
+		sensitiveAttr, sensitiveExist := variable.Body.Attributes["sensitive"]
+		// Check if "sensitive" attribute exist.
+		if sensitiveExist {
+			// Check if "sensitive" attribute value is a literal `true`.
+			sensitiveValue, ok := literalValue(sensitiveAttr.Expr)
+			if !ok || sensitiveValue.Type() != cty.Bool {
+				err := runner.EmitIssue(
+					r,
+					fmt.Sprintf("variable `%s` sensitive must be a literal boolean", variable.Labels[0]),
+					sensitiveAttr.Range,
+				)
+				if err != nil {
+					return err
+				}
+			} else if !sensitiveValue.True() {
+				err := runner.EmitIssue(
+					r,
+					fmt.Sprintf("variable `%s` must have `sensitive = true` attribute defined", variable.Labels[0]),
+					sensitiveAttr.Range,
+				)
+				if err != nil {
+					return err
+				}
+			}
+		} else {
+			err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("variable `%s` is missing the `sensitive` attribute", variable.Labels[0]),
+				variable.DefRange,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Check if "default" attribute has a literal value, secrets must not be committed
+		// to the module. `default = null` is allowed since it does not hold any secret.
+		if defaultAttr, defaultExist := variable.Body.Attributes["default"]; defaultExist {
+			if defaultValue, diags := defaultAttr.Expr.Value(nil); !diags.HasErrors() && !defaultValue.IsNull() {
+				err := runner.EmitIssue(
+					r,
+					fmt.Sprintf("variable `%s` must not have a literal `default` value", variable.Labels[0]),
+					defaultAttr.Range,
+				)
+				if err != nil {
+					return err
 				}
+			}
+		}
+	}
+
+	// Check if the required variables follow their schema contracts.
+	for _, variable := range variables.Blocks {
+		contract, exists := contracts[variable.Labels[0]]
+		if !exists {
+			continue
+		}
+		if err := r.checkContract(runner, variable, contract); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-				// Check if "sensitive" attribute value is `true`.
-				if !sensitiveValue.Val.True() {
-					err := runner.EmitIssue(
-						r,
-						fmt.Sprintf("variable `%s` must have `sensitive = true` attribute defined", variable.Labels[0]),
-						sensitiveAttr.Range,
-					)
-					if err != nil {
-						return err
-					}
+// checkContract checks the type, nullable, sensitive and default attributes of the
+// variable against its schema contract.
+func (r *TerraformRequiredVariables) checkContract(runner tflint.Runner, variable *hclext.Block, contract requiredVariableContract) error {
+	name := variable.Labels[0]
+
+	// Types are compared structurally, so formatting differences such as the order of
+	// object attributes do not matter. The defaults of optional attributes are not compared.
+	if contract.expectedType != cty.NilType {
+		typeAttr, typeExist := variable.Body.Attributes["type"]
+		if !typeExist {
+			err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("variable `%s` must have type `%s`", name, typeString(contract.expectedType)),
+				variable.DefRange,
+			)
+			if err != nil {
+				return err
+			}
+		} else if typeExpr, ok := nativeTypeExpr(runner, typeAttr.Expr); ok {
+			declaredType, _, diags := typeexpr.TypeConstraintWithDefaults(typeExpr)
+			if diags.HasErrors() {
+				err := runner.EmitIssue(
+					r,
+					fmt.Sprintf("variable `%s` type cannot be parsed, expected type `%s`: %s", name, typeString(contract.expectedType), diags[0].Summary),
+					typeAttr.Range,
+				)
+				if err != nil {
+					return err
 				}
-			} else {
+			} else if !declaredType.Equals(contract.expectedType) {
 				err := runner.EmitIssue(
 					r,
-					fmt.Sprintf("variable `%s` is missing the `sensitive` attribute", variable.Labels[0]),
-					variable.DefRange,
+					fmt.Sprintf("variable `%s` type `%s` does not match the expected type `%s`", name, typeString(declaredType), typeString(contract.expectedType)),
+					typeAttr.Range,
 				)
 				if err != nil {
 					return err
@@ -152,5 +383,151 @@ func (r *TerraformRequiredVariables) Check(runner tflint.Runner) error {
 		}
 	}
 
+	// Variables are nullable unless `nullable = false` is declared.
+	if contract.Nullable != nil {
+		nullable := true
+		rng := variable.DefRange
+		if nullableAttr, nullableExist := variable.Body.Attributes["nullable"]; nullableExist {
+			rng = nullableAttr.Range
+			if val, ok := literalValue(nullableAttr.Expr); ok && val.Type() == cty.Bool {
+				nullable = val.True()
+			}
+		}
+		if nullable != *contract.Nullable {
+			err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("variable `%s` must have `nullable = %t`", name, *contract.Nullable),
+				rng,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// `sensitive = true` is checked by the sensitive variable policy.
+	if contract.Sensitive != nil && !*contract.Sensitive {
+		if sensitiveAttr, sensitiveExist := variable.Body.Attributes["sensitive"]; sensitiveExist {
+			if val, ok := literalValue(sensitiveAttr.Expr); ok && val.Type() == cty.Bool && val.True() {
+				err := runner.EmitIssue(
+					r,
+					fmt.Sprintf("variable `%s` must not have `sensitive = true`", name),
+					sensitiveAttr.Range,
+				)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if contract.NoDefault {
+		if defaultAttr, defaultExist := variable.Body.Attributes["default"]; defaultExist {
+			err := runner.EmitIssue(
+				r,
+				fmt.Sprintf("variable `%s` must not have `default` attribute", name),
+				defaultAttr.Range,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
+
+// anchorFile returns the file where the missing variables are reported, which is
+// variables.tf of the module, or the first file declaring variables if there is no
+// variables.tf. If there are no variables at all, the first file of the module is used.
+func (r *TerraformRequiredVariables) anchorFile(runner tflint.Runner, variables *hclext.BodyContent) (string, *hcl.File, error) {
+	files, err := runner.GetFiles()
+	if err != nil {
+		return "", nil, err
+	}
+
+	filenames := slices.Sorted(maps.Keys(files))
+	for _, filename := range filenames {
+		if filepath.Base(filename) == "variables.tf" {
+			return filename, files[filename], nil
+		}
+	}
+
+	var declaringFiles []string
+	for _, variable := range variables.Blocks {
+		declaringFiles = append(declaringFiles, variable.DefRange.Filename)
+	}
+	slices.Sort(declaringFiles)
+	// Files in native syntax are preferred, since the stubs cannot be appended to JSON syntax files.
+	slices.SortStableFunc(declaringFiles, func(a, b string) int {
+		return cmp.Compare(boolToInt(isJSONSyntax(a)), boolToInt(isJSONSyntax(b)))
+	})
+	if len(declaringFiles) > 0 {
+		return declaringFiles[0], files[declaringFiles[0]], nil
+	}
+
+	if len(filenames) > 0 {
+		return filenames[0], files[filenames[0]], nil
+	}
+	return "", nil, nil
+}
+
+// variableStubs returns the variable blocks of the missing variables generated from
+// the stub template and the schema contracts.
+func (r *TerraformRequiredVariables) variableStubs(names []string, config *terraformRequiredVariablesConfig, contracts map[string]requiredVariableContract, sensitiveVarPatterns []*regexp.Regexp) string {
+	template := terraformRequiredVariablesStubConfig{Type: "string"}
+	if config.StubTemplate != nil {
+		template = *config.StubTemplate
+		if template.Type == "" {
+			template.Type = "string"
+		}
+	}
+
+	file := hclwrite.NewEmptyFile()
+	for _, name := range names {
+		typeString := template.Type
+		description := strings.ReplaceAll(template.Description, "{name}", name)
+		sensitive := template.Sensitive || r.isSensitiveVar(name, config.SensitiveVars, sensitiveVarPatterns)
+		if contract, exists := contracts[name]; exists {
+			if contract.Type != "" {
+				typeString = contract.Type
+			}
+			if contract.Description != "" {
+				description = contract.Description
+			}
+		}
+
+		// `sensitive` is placed as the first attribute of sensitive variables.
+		file.Body().AppendNewline()
+		body := file.Body().AppendNewBlock("variable", []string{name}).Body()
+		if sensitive {
+			body.SetAttributeValue("sensitive", cty.True)
+		}
+		body.SetAttributeRaw("type", hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(typeString)}})
+		if description != "" {
+			body.SetAttributeValue("description", cty.StringVal(description))
+		}
+	}
+	return string(hclwrite.Format(file.Bytes()))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// isSensitiveVar returns whether the variable name is listed in sensitive_vars or matches
+// any of sensitive_var_patterns.
+func (r *TerraformRequiredVariables) isSensitiveVar(name string, sensitiveVars []string, patterns []*regexp.Regexp) bool {
+	if slices.Contains(sensitiveVars, name) {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
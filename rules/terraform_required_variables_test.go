@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"maps"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -13,6 +14,7 @@ func Test_TerraformRequredVariables(t *testing.T) {
 		Content  string
 		Config   string
 		Expected helper.Issues
+		Fixed    string
 	}{
 		{
 			Name: "module with complete required variables and correct attribute.",
@@ -61,11 +63,30 @@ variable "my_variable" {
 					Rule:    NewTerraformRequiredVariables(),
 					Message: "required variable(s) not declared: cloud_creds, module_info, module_tmpl",
 					Range: hcl.Range{
-						Start: hcl.Pos{Line: 1, Column: 1},
-						End:   hcl.Pos{Line: 1, Column: 1},
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 1},
 					},
 				},
 			},
+			Fixed: `
+variable "my_variable" {
+  type = string
+}
+
+variable "cloud_creds" {
+  sensitive = true
+  type      = string
+}
+
+variable "module_info" {
+  type = string
+}
+
+variable "module_tmpl" {
+  type = string
+}
+`,
 		},
 		{
 			Name: "module with incomplete required variables. (no module_info), and no sensitive attribute on `cloud_creds`.",
@@ -84,8 +105,9 @@ variable "module_tmpl" {
 					Rule:    NewTerraformRequiredVariables(),
 					Message: "required variable(s) not declared: module_info",
 					Range: hcl.Range{
-						Start: hcl.Pos{Line: 1, Column: 1},
-						End:   hcl.Pos{Line: 1, Column: 1},
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 1},
 					},
 				},
 				{
@@ -98,6 +120,19 @@ variable "module_tmpl" {
 					},
 				},
 			},
+			Fixed: `
+variable "cloud_creds" {
+  type = string
+}
+
+variable "module_tmpl" {
+  type = string
+}
+
+variable "module_info" {
+  type = string
+}
+`,
 		},
 		{
 			Name: "module with incomplete required variables. (no module_info & module_tmpl), but with sensitive attribute on `cloud_creds`.",
@@ -113,11 +148,26 @@ variable "cloud_creds" {
 					Rule:    NewTerraformRequiredVariables(),
 					Message: "required variable(s) not declared: module_info, module_tmpl",
 					Range: hcl.Range{
-						Start: hcl.Pos{Line: 1, Column: 1},
-						End:   hcl.Pos{Line: 1, Column: 1},
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 1},
 					},
 				},
 			},
+			Fixed: `
+variable "cloud_creds" {
+  sensitive = true
+  type      = string
+}
+
+variable "module_info" {
+  type = string
+}
+
+variable "module_tmpl" {
+  type = string
+}
+`,
 		},
 		{
 			Name: "module with complete required variables, but no senstitive attribute",
@@ -166,15 +216,25 @@ rule "terraform_required_variables" {
 					Rule:    NewTerraformRequiredVariables(),
 					Message: "required variable(s) not declared: additional_required_var",
 					Range: hcl.Range{
-						Filename: "",
+						Filename: "main.tf",
 						Start:    hcl.Pos{Line: 1, Column: 1},
 						End:      hcl.Pos{Line: 1, Column: 1},
 					},
 				},
 			},
+			Fixed: `
+variable "cloud_creds" {
+  sensitive = true
+  type      = string
+}
+
+variable "additional_required_var" {
+  type = string
+}
+`,
 		},
 		{
-			Name: "variable cloud_creds have invalid sensitive placement and value.",
+			Name: "variable cloud_creds have invalid sensitive value.",
 			Content: `
 variable "cloud_creds" {
   type      = string
@@ -197,13 +257,277 @@ rule "terraform_required_variables" {
 						End:      hcl.Pos{Line: 4, Column: 20},
 					},
 				},
+			},
+		},
+		{
+			Name: "sensitive variables matching exact names and patterns.",
+			Content: `
+variable "db_password" {
+  sensitive = true
+  type      = string
+}
+
+variable "api_token" {
+  type = string
+}
+
+variable "my_secret" {
+  type      = string
+  sensitive = true
+}
+
+variable "db_password_hint" {
+  type = string
+}
+
+variable "cloud_creds" {
+  type = string
+}
+`,
+			Config: testTerraformRequiredVariablesSensitiveConfig,
+			Expected: helper.Issues{
 				{
 					Rule:    NewTerraformRequiredVariables(),
-					Message: "variable `cloud_creds` must place `sensitive = true` as first parameter after variable definition",
+					Message: "variable `api_token` is missing the `sensitive` attribute",
 					Range: hcl.Range{
 						Filename: "main.tf",
-						Start:    hcl.Pos{Line: 4, Column: 3},
-						End:      hcl.Pos{Line: 4, Column: 20},
+						Start:    hcl.Pos{Line: 7, Column: 1},
+						End:      hcl.Pos{Line: 7, Column: 21},
+					},
+				},
+			},
+		},
+		{
+			Name: "sensitive variable with a comment above sensitive.",
+			Content: `
+variable "cloud_creds" {
+  # the creds
+  sensitive = true
+  type      = string
+}
+`,
+			Config: `
+rule "terraform_required_variables" {
+  enabled       = true
+  required_vars = ["cloud_creds"]
+}
+`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "sensitive variable with non literal sensitive value.",
+			Content: `
+variable "db_password" {
+  sensitive = "true"
+  type      = string
+}
+`,
+			Config: testTerraformRequiredVariablesSensitiveConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `db_password` sensitive must be a literal boolean",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 3, Column: 21},
+					},
+				},
+			},
+		},
+		{
+			Name: "sensitive variables with literal default value.",
+			Content: `
+variable "db_password" {
+  sensitive = true
+  type      = string
+  default   = "my-password"
+}
+
+variable "api_token" {
+  sensitive = true
+  type      = object({ value = string })
+  default   = { value = "my-token" }
+}
+
+variable "my_secret" {
+  sensitive = true
+  type      = string
+  default   = null
+}
+`,
+			Config: testTerraformRequiredVariablesSensitiveConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `db_password` must not have a literal `default` value",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3},
+						End:      hcl.Pos{Line: 5, Column: 28},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `api_token` must not have a literal `default` value",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 11, Column: 3},
+						End:      hcl.Pos{Line: 11, Column: 37},
+					},
+				},
+			},
+		},
+		{
+			Name: "required variables following schema contracts.",
+			Content: `
+variable "cloud_creds" {
+  sensitive = true
+  type      = string
+}
+
+variable "module_info" {
+  type = object({
+    id   = number
+    name = string
+  })
+  nullable = false
+}
+
+variable "module_tmpl" {
+  type    = map(string)
+  default = {}
+}
+`,
+			Config:   testTerraformRequiredVariablesContractConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "required variables violating schema contracts.",
+			Content: `
+variable "cloud_creds" {
+  type = string
+}
+
+variable "module_info" {
+  type    = object({ name = string })
+  default = null
+}
+
+variable "module_tmpl" {
+  sensitive = true
+}
+`,
+			Config: testTerraformRequiredVariablesContractConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `cloud_creds` is missing the `sensitive` attribute",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 23},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `module_info` type `object({name=string})` does not match the expected type `object({id=number,name=string})`",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 7, Column: 3},
+						End:      hcl.Pos{Line: 7, Column: 38},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `module_info` must have `nullable = false`",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 6, Column: 1},
+						End:      hcl.Pos{Line: 6, Column: 23},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `module_info` must not have `default` attribute",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 8, Column: 3},
+						End:      hcl.Pos{Line: 8, Column: 17},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `module_tmpl` must have type `map(string)`",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 11, Column: 1},
+						End:      hcl.Pos{Line: 11, Column: 23},
+					},
+				},
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `module_tmpl` must not have `sensitive = true`",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 12, Column: 3},
+						End:      hcl.Pos{Line: 12, Column: 19},
+					},
+				},
+			},
+		},
+		{
+			Name: "required variable with optional attributes and defaults matching the contract.",
+			Content: `
+variable "module_info" {
+  type = object({
+    name = string
+    tags = optional(map(string), {})
+  })
+}
+`,
+			Config:   testTerraformRequiredVariablesOptionalConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "required variable with optional attributes and defaults violating the contract.",
+			Content: `
+variable "module_info" {
+  type = object({
+    name = number
+    tags = optional(map(string), {})
+  })
+}
+`,
+			Config: testTerraformRequiredVariablesOptionalConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `module_info` type `object({name=number,tags=optional(map(string))})` does not match the expected type `object({name=string,tags=optional(map(string))})`",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 6, Column: 5},
+					},
+				},
+			},
+		},
+		{
+			Name: "required variable with a type which cannot be parsed.",
+			Content: `
+variable "module_info" {
+  type = object({ name = strng })
+}
+`,
+			Config: testTerraformRequiredVariablesOptionalConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `module_info` type cannot be parsed, expected type `object({name=string,tags=optional(map(string))})`: Invalid type specification",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 3, Column: 34},
 					},
 				},
 			},
@@ -223,12 +547,313 @@ rule "terraform_required_variables" {
 			}
 
 			helper.AssertIssues(t, test.Expected, runner.Issues)
+
+			want := map[string]string{}
+			if test.Fixed != "" {
+				want["main.tf"] = test.Fixed
+			}
+			helper.AssertChanges(t, want, runner.Changes())
 		})
 	}
 }
 
+func Test_TerraformRequredVariables_MissingVariables(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Files    map[string]string
+		Config   string
+		Expected helper.Issues
+		Fixed    map[string]string
+	}{
+		{
+			Name: "missing variables are reported in variables.tf.",
+			Files: map[string]string{
+				"main.tf": `
+variable "cloud_creds" {
+  sensitive = true
+  type      = string
+}
+`,
+				"variables.tf": `
+variable "module_info" {
+  type = string
+}`,
+			},
+			Config: testTerraformRequiredVariablesConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "required variable(s) not declared: module_tmpl",
+					Range: hcl.Range{
+						Filename: "variables.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 1},
+					},
+				},
+			},
+			Fixed: map[string]string{
+				"variables.tf": `
+variable "module_info" {
+  type = string
+}
+
+variable "module_tmpl" {
+  type = string
+}
+`,
+			},
+		},
+		{
+			Name: "missing variables are reported in the first file declaring variables.",
+			Files: map[string]string{
+				"main.tf": `
+resource "foo" "my_resource" {
+  name = "my name"
+}
+`,
+				"outputs.tf": `
+variable "module_info" {
+  type = string
+}
+`,
+				"inputs.tf": `
+variable "cloud_creds" {
+  sensitive = true
+  type      = string
+}
+`,
+			},
+			Config: testTerraformRequiredVariablesStubConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "required variable(s) not declared: module_tmpl, db_password",
+					Range: hcl.Range{
+						Filename: "inputs.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 1},
+					},
+				},
+			},
+			Fixed: map[string]string{
+				"inputs.tf": `
+variable "cloud_creds" {
+  sensitive = true
+  type      = string
+}
+
+variable "module_tmpl" {
+  type        = any
+  description = "The module_tmpl variable."
+}
+
+variable "db_password" {
+  sensitive   = true
+  type        = object({ username = string, password = string })
+  description = "The credentials of the database."
+}
+`,
+			},
+		},
+		{
+			Name: "missing variables are reported in JSON syntax file without autofix.",
+			Files: map[string]string{
+				"main.tf.json": `{
+  "variable": {
+    "cloud_creds": {
+      "sensitive": true,
+      "type": "string"
+    }
+  }
+}`,
+			},
+			Config: testTerraformRequiredVariablesConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "required variable(s) not declared: module_info, module_tmpl",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 1},
+					},
+				},
+			},
+			Fixed: map[string]string{},
+		},
+	}
+
+	rule := NewTerraformRequiredVariables()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			files := map[string]string{".tflint.hcl": test.Config}
+			maps.Copy(files, test.Files)
+			runner := helper.TestRunner(t, files)
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+			helper.AssertChanges(t, test.Fixed, runner.Changes())
+		})
+	}
+}
+
+const testTerraformRequiredVariablesStubConfig = `
+rule "terraform_required_variables" {
+  enabled       = true
+  required_vars = ["cloud_creds", "module_info", "module_tmpl"]
+
+  variable "db_password" {
+    type        = "object({ username = string, password = string })"
+    sensitive   = true
+    description = "The credentials of the database."
+  }
+
+  stub_template {
+    type        = "any"
+    description = "The {name} variable."
+  }
+}
+`
+
+const testTerraformRequiredVariablesSensitiveConfig = `
+rule "terraform_required_variables" {
+  enabled                = true
+  required_vars          = ["db_password"]
+  sensitive_vars         = ["db_password"]
+  sensitive_var_patterns = [".*_token", ".*_secret"]
+}
+`
+
+const testTerraformRequiredVariablesContractConfig = `
+rule "terraform_required_variables" {
+  enabled = true
+
+  variable "cloud_creds" {
+    sensitive = true
+  }
+
+  variable "module_info" {
+    type       = "object({ name = string, id = number })"
+    nullable   = false
+    no_default = true
+  }
+
+  variable "module_tmpl" {
+    type      = "map(string)"
+    sensitive = false
+  }
+}
+`
+
+const testTerraformRequiredVariablesOptionalConfig = `
+rule "terraform_required_variables" {
+  enabled       = true
+  required_vars = ["module_info"]
+
+  variable "module_info" {
+    type = "object({ name = string, tags = optional(map(string), {}) })"
+  }
+}
+`
+
 const testTerraformRequiredVariablesConfig = `
 rule "terraform_required_variables" {
   enabled = true
 }
 `
+
+func Test_TerraformRequredVariables_JSON(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Config   string
+		Expected helper.Issues
+	}{
+		{
+			Name: "module with complete required variables and correct attribute, sensitive is not the first parameter.",
+			Content: `{
+  "variable": {
+    "cloud_creds": {
+      "type": "string",
+      "sensitive": true
+    },
+    "module_tmpl": {
+      "type": "string"
+    },
+    "module_info": {
+      "type": "string"
+    }
+  }
+}`,
+			Config:   testTerraformRequiredVariablesConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "variable cloud_creds have invalid sensitive value.",
+			Content: `{
+  "variable": {
+    "cloud_creds": {
+      "type": "string",
+      "sensitive": false
+    }
+  }
+}`,
+			Config: `
+rule "terraform_required_variables" {
+  enabled       = true
+  required_vars = ["cloud_creds"]
+}
+`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformRequiredVariables(),
+					Message: "variable `cloud_creds` must have `sensitive = true` attribute defined",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 5, Column: 7},
+						End:      hcl.Pos{Line: 5, Column: 25},
+					},
+				},
+			},
+		},
+		{
+			Name: "required variables following schema contracts.",
+			Content: `{
+  "variable": {
+    "cloud_creds": {
+      "sensitive": true,
+      "type": "string"
+    },
+    "module_info": {
+      "type": "object({ id = number, name = string })",
+      "nullable": false
+    },
+    "module_tmpl": {
+      "type": "map(string)"
+    }
+  }
+}`,
+			Config:   testTerraformRequiredVariablesContractConfig,
+			Expected: helper.Issues{},
+		},
+	}
+
+	rule := NewTerraformRequiredVariables()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf.json": test.Content,
+				".tflint.hcl":  test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
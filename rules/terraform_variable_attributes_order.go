@@ -0,0 +1,249 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/myklst/tflint-ruleset-myklst/project"
+	"github.com/terraform-linters/tflint-plugin-sdk/logger"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TerraformVariableAttributesOrder checks the order of attributes and blocks in variables
+type TerraformVariableAttributesOrder struct {
+	tflint.DefaultRule
+}
+
+type terraformVariableAttributesOrderConfig struct {
+	Order []string `hclext:"order,optional"`
+}
+
+// defaultVariableAttributesOrder is the order of attributes and nested blocks in
+// variables when `order` is not specified.
+var defaultVariableAttributesOrder = []string{"type", "description", "default", "sensitive", "nullable", "validation"}
+
+// variableAttributes are the attributes and nested blocks of variables, which can be listed in `order`.
+var variableAttributes = []string{"type", "description", "default", "sensitive", "nullable", "ephemeral", "validation"}
+
+// variableAttribute is an attribute or a nested block listed in `order`.
+type variableAttribute struct {
+	name      string
+	rank      int
+	rng       hcl.Range
+	startLine int
+}
+
+// validate checks the attributes listed in `order`, and sets the default order.
+func (config *terraformVariableAttributesOrderConfig) validate() error {
+	if len(config.Order) == 0 {
+		config.Order = defaultVariableAttributesOrder
+	}
+	for i, name := range config.Order {
+		key := fmt.Sprintf("order[%d]", i)
+		if err := validateOneOf(key, name, variableAttributes); err != nil {
+			return err
+		}
+		if slices.Contains(config.Order[:i], name) {
+			return configErrorf(key, "`%s` is listed more than once", name)
+		}
+	}
+	return nil
+}
+
+// NewTerraformVariableAttributesOrder returns a new rule
+func NewTerraformVariableAttributesOrder() *TerraformVariableAttributesOrder {
+	return &TerraformVariableAttributesOrder{}
+}
+
+// Name returns the rule name
+func (r *TerraformVariableAttributesOrder) Name() string {
+	return "terraform_variable_attributes_order"
+}
+
+// Enabled returns whether the rule is enabled by default
+func (r *TerraformVariableAttributesOrder) Enabled() bool {
+	return true
+}
+
+// Severity returns the rule severity
+func (r *TerraformVariableAttributesOrder) Severity() tflint.Severity {
+	return tflint.WARNING
+}
+
+// Link returns the rule reference link
+func (r *TerraformVariableAttributesOrder) Link() string {
+	return project.ReferenceLink(r.Name())
+}
+
+// Check checks whether the attributes and blocks in variables follow the configured order
+func (r *TerraformVariableAttributesOrder) Check(runner tflint.Runner) error {
+	config := &terraformVariableAttributesOrderConfig{}
+
+	if err := decodeRuleConfig(runner, r, config); err != nil {
+		return err
+	}
+
+	files, err := runner.GetFiles()
+	if err != nil {
+		return err
+	}
+
+	for filename, file := range files {
+		// The order of attributes is a layout check, which does not apply to JSON syntax.
+		if isJSONSyntax(filename) {
+			logger.Debug(fmt.Sprintf("skip checking variable attributes order in JSON syntax file '%s'", filename))
+			continue
+		}
+
+		fileContents, _, diags := file.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{
+				{
+					Type:       "variable",
+					LabelNames: []string{"name"},
+				},
+			},
+		})
+		if diags.HasErrors() {
+			return diags
+		}
+
+		// Lex the file once, tokens are looked up by line for all variables in the file.
+		tokens := newLineTokens(file.Bytes, filename)
+
+		for _, variable := range fileContents.Blocks {
+			body := variable.Body.(*hclsyntax.Body)
+			variableName := variable.Labels[0]
+
+			// Sensitive variables must place `sensitive` before any other attributes and blocks.
+			sensitiveFirst := r.isSensitive(body)
+			order := config.Order
+			if sensitiveFirst {
+				order = slices.Concat([]string{"sensitive"}, slices.DeleteFunc(slices.Clone(order), func(name string) bool { return name == "sensitive" }))
+			}
+
+			// fix rearranges the whole variable body, so every issue in the same variable shares it.
+			fix := func(f tflint.Fixer) error {
+				rng, text, ok := r.rearrangeAttributes(tokens, order, sensitiveFirst, body)
+				if !ok {
+					return tflint.ErrFixNotSupported
+				}
+				return f.ReplaceText(rng, text)
+			}
+
+			attrs := r.findAttributes(body, order)
+			ranks := make([]int, len(attrs))
+			for i, attr := range attrs {
+				ranks[i] = attr.rank
+			}
+			ordered := orderedRanks(ranks)
+
+			firstLine := body.EndRange.Start.Line
+			for _, attr := range body.Attributes {
+				firstLine = min(firstLine, attr.SrcRange.Start.Line)
+			}
+			for _, block := range body.Blocks {
+				firstLine = min(firstLine, block.Range().Start.Line)
+			}
+
+			for i, attr := range attrs {
+				var message string
+				switch {
+				case sensitiveFirst && attr.name == "sensitive":
+					if attr.startLine != firstLine {
+						message = fmt.Sprintf("variable '%s' must place 'sensitive' as the first attribute", variableName)
+					}
+				case !ordered[i]:
+					message = fmt.Sprintf("variable '%s' has invalid '%s' attribute arrangement", variableName, attr.name)
+				}
+				if message == "" {
+					continue
+				}
+				if err := runner.EmitIssueWithFix(r, message, attr.rng, fix); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSensitive returns whether the variable is declared with `sensitive = true`.
+func (r *TerraformVariableAttributesOrder) isSensitive(body *hclsyntax.Body) bool {
+	attr, exists := body.Attributes["sensitive"]
+	if !exists {
+		return false
+	}
+	val, ok := literalValue(attr.Expr)
+	return ok && val.Type() == cty.Bool && val.True()
+}
+
+// findAttributes looks up the attributes and nested blocks listed in the order. They
+// are sorted by their position in the variable body.
+func (r *TerraformVariableAttributesOrder) findAttributes(body *hclsyntax.Body, order []string) []variableAttribute {
+	var attrs []variableAttribute
+	for name, attr := range body.Attributes {
+		if rank := slices.Index(order, name); rank >= 0 {
+			attrs = append(attrs, variableAttribute{name: name, rank: rank, rng: attr.SrcRange, startLine: attr.SrcRange.Start.Line})
+		}
+	}
+	for _, block := range body.Blocks {
+		if rank := slices.Index(order, block.Type); rank >= 0 {
+			attrs = append(attrs, variableAttribute{name: block.Type, rank: rank, rng: block.DefRange(), startLine: block.Range().Start.Line})
+		}
+	}
+
+	slices.SortFunc(attrs, func(a, b variableAttribute) int { return a.startLine - b.startLine })
+	return attrs
+}
+
+// rearrangeAttributes rewrites the variable body to place the attributes and blocks
+// listed in the order into the positions where they are found, following the order.
+// Other attributes, blocks and comment lines stay at their original positions. For
+// sensitive variables, `sensitive` is moved to the top of the variable body.
+func (r *TerraformVariableAttributesOrder) rearrangeAttributes(tokens *lineTokens, order []string, sensitiveFirst bool, body *hclsyntax.Body) (hcl.Range, string, bool) {
+	items, ok := tokens.bodySegments(body)
+	if !ok {
+		return hcl.Range{}, "", false
+	}
+
+	var listed, sensitive []bodySegment
+	for _, item := range items {
+		switch {
+		case sensitiveFirst && item.name == "sensitive":
+			sensitive = append(sensitive, item)
+		case item.name != "" && slices.Contains(order, item.name):
+			listed = append(listed, item)
+		}
+	}
+	// Stable sort keeps multiple blocks of the same type, such as validation, in their original order.
+	slices.SortStableFunc(listed, func(a, b bodySegment) int {
+		return slices.Index(order, a.name) - slices.Index(order, b.name)
+	})
+
+	var sb strings.Builder
+	for _, item := range sensitive {
+		sb.WriteString(tokens.segmentText(item))
+	}
+	next := 0
+	for _, item := range items {
+		if sensitiveFirst && item.name == "sensitive" {
+			continue
+		}
+		// Blank lines belong to the position, so the spacing of the body is kept.
+		if sb.Len() > 0 && item.blankBefore {
+			sb.WriteString("\n")
+		}
+		if item.name != "" && slices.Contains(order, item.name) {
+			item = listed[next]
+			next++
+		}
+		sb.WriteString(tokens.segmentText(item))
+	}
+
+	return tokens.bodyLinesRange(body), sb.String(), true
+}
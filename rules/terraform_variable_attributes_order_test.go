@@ -0,0 +1,279 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/helper"
+)
+
+func Test_TerraformVariableAttributesOrder(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Config   string
+		Expected helper.Issues
+		Fixed    string
+	}{
+		{
+			Name: "variable without attributes",
+			Content: `
+variable "my_var" {}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "variable with attributes in order",
+			Content: `
+variable "my_var" {
+  type        = string
+  description = "my description"
+  default     = "foo"
+  nullable    = false
+
+  validation {
+    condition     = length(var.my_var) > 0
+    error_message = "must not be empty."
+  }
+
+  validation {
+    condition     = length(var.my_var) < 10
+    error_message = "must be shorter than 10."
+  }
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "variable with attributes in order and unlisted attributes",
+			Content: `
+variable "my_var" {
+  ephemeral   = true
+  type        = string
+  description = "my description"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "sensitive variable with sensitive first",
+			Content: `
+variable "my_var" {
+  # The password of the database.
+  sensitive   = true
+  type        = string
+  description = "my description"
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "non sensitive variable in order",
+			Content: `
+variable "my_var" {
+  type      = bool
+  default   = false
+  sensitive = false
+}`,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "variable with attributes, invalid arrangement",
+			Content: `
+variable "my_var" {
+  description = "my description"
+  # The type of my variable.
+  type    = string
+  default = "foo"
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformVariableAttributesOrder(),
+					Message: "variable 'my_var' has invalid 'type' attribute arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3},
+						End:      hcl.Pos{Line: 5, Column: 19},
+					},
+				},
+			},
+			Fixed: `
+variable "my_var" {
+  # The type of my variable.
+  type        = string
+  description = "my description"
+  default     = "foo"
+}`,
+		},
+		{
+			Name: "variable with validation before attributes, invalid arrangement",
+			Content: `
+variable "my_var" {
+  type = string
+
+  validation {
+    condition     = length(var.my_var) > 0
+    error_message = "must not be empty."
+  }
+  nullable = false
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformVariableAttributesOrder(),
+					Message: "variable 'my_var' has invalid 'nullable' attribute arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 9, Column: 3},
+						End:      hcl.Pos{Line: 9, Column: 19},
+					},
+				},
+			},
+			Fixed: `
+variable "my_var" {
+  type = string
+
+  nullable = false
+  validation {
+    condition     = length(var.my_var) > 0
+    error_message = "must not be empty."
+  }
+}`,
+		},
+		{
+			Name: "sensitive variable without sensitive first, invalid arrangement",
+			Content: `
+variable "my_var" {
+  type        = string
+  description = "my description"
+  # Never show the password.
+  sensitive   = true
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformVariableAttributesOrder(),
+					Message: "variable 'my_var' must place 'sensitive' as the first attribute",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 6, Column: 3},
+						End:      hcl.Pos{Line: 6, Column: 21},
+					},
+				},
+			},
+			Fixed: `
+variable "my_var" {
+  # Never show the password.
+  sensitive   = true
+  type        = string
+  description = "my description"
+}`,
+		},
+		{
+			Name: "sensitive variable after unlisted attribute, invalid arrangement",
+			Content: `
+variable "my_var" {
+  ephemeral = true
+  sensitive = true
+  type      = string
+}`,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformVariableAttributesOrder(),
+					Message: "variable 'my_var' must place 'sensitive' as the first attribute",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 3},
+						End:      hcl.Pos{Line: 4, Column: 19},
+					},
+				},
+			},
+			Fixed: `
+variable "my_var" {
+  sensitive = true
+  ephemeral = true
+  type      = string
+}`,
+		},
+		{
+			Name: "variable with attributes in custom order",
+			Content: `
+variable "my_var" {
+  description = "my description"
+  type        = string
+  default     = "foo"
+}`,
+			Config:   testTerraformVariableAttributesOrderConfig,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "variable with attributes in custom order, invalid arrangement",
+			Content: `
+variable "my_var" {
+  type        = string
+  description = "my description"
+}`,
+			Config: testTerraformVariableAttributesOrderConfig,
+			Expected: helper.Issues{
+				{
+					Rule:    NewTerraformVariableAttributesOrder(),
+					Message: "variable 'my_var' has invalid 'description' attribute arrangement",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 4, Column: 3},
+						End:      hcl.Pos{Line: 4, Column: 33},
+					},
+				},
+			},
+			Fixed: `
+variable "my_var" {
+  description = "my description"
+  type        = string
+}`,
+		},
+	}
+
+	rule := NewTerraformVariableAttributesOrder()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf":     test.Content,
+				".tflint.hcl": test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+
+			want := map[string]string{}
+			if test.Fixed != "" {
+				want["main.tf"] = test.Fixed
+			}
+			helper.AssertChanges(t, want, runner.Changes())
+		})
+	}
+}
+
+func Test_TerraformVariableAttributesOrder_JSON(t *testing.T) {
+	runner := helper.TestRunner(t, map[string]string{
+		"main.tf.json": `{
+  "variable": {
+    "my_var": {
+      "default": "foo",
+      "type": "string",
+      "sensitive": true
+    }
+  }
+}`,
+	})
+
+	if err := NewTerraformVariableAttributesOrder().Check(runner); err != nil {
+		t.Fatalf("Unexpected error occurred: %s", err)
+	}
+
+	helper.AssertIssues(t, helper.Issues{}, runner.Issues)
+	helper.AssertChanges(t, map[string]string{}, runner.Changes())
+}
+
+const testTerraformVariableAttributesOrderConfig = `
+rule "terraform_variable_attributes_order" {
+  enabled = true
+  order   = ["description", "type", "default"]
+}
+`
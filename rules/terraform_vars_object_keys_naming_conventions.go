@@ -2,7 +2,9 @@ package rules
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -21,6 +23,9 @@ type terraformVarsObjectKeysNamingConventionsConfig struct {
 	Format          string                         `hclext:"format,optional"`
 	CustomFormatKey string                         `hclext:"custom_format_key,optional"`
 	CustomFormats   map[string]*CustomFormatConfig `hclext:"custom_formats,optional"`
+
+	// nameValidator is nil if the format is "none".
+	nameValidator *NameValidator
 }
 
 // CustomFormatConfig defines a custom format that can be used instead of the predefined formats
@@ -82,10 +87,16 @@ func (r *TerraformVarsObjectKeysNamingConventions) Check(runner tflint.Runner) e
 		Format: "snake_case",
 	}
 
-	if err := runner.DecodeRuleConfig(r.Name(), config); err != nil {
+	if err := decodeRuleConfig(runner, r, config); err != nil {
 		return err
 	}
 
+	// Names are not checked with the "none" format.
+	nameValidator := config.nameValidator
+	if nameValidator == nil {
+		return nil
+	}
+
 	// Fetch all variable blocks with type attributes
 	variables, err := runner.GetModuleContent(&hclext.BodySchema{
 		Blocks: []hclext.BlockSchema{
@@ -104,12 +115,6 @@ func (r *TerraformVarsObjectKeysNamingConventions) Check(runner tflint.Runner) e
 		return err
 	}
 
-	// Initialize the name validator
-	nameValidator, err := config.getNameValidator()
-	if err != nil {
-		return err
-	}
-
 	// Loop through each variable declared
 	for _, variable := range variables.Blocks {
 		variableName := variable.Labels[0]
@@ -138,8 +143,9 @@ func (r *TerraformVarsObjectKeysNamingConventions) Check(runner tflint.Runner) e
 			continue
 		}
 
-		// Convert hcl.Expression to hclsyntax.Expression
-		syntaxExpr, ok := typeAttr.Expr.(hclsyntax.Expression)
+		// Convert hcl.Expression to hclsyntax.Expression, type constraints in JSON syntax are
+		// strings, which are parsed into native syntax.
+		syntaxExpr, ok := nativeTypeExpr(runner, typeAttr.Expr)
 		if !ok {
 			continue
 		}
@@ -196,43 +202,61 @@ func (nameValidator *NameValidator) validate(
 	return nil
 }
 
-func (config *terraformVarsObjectKeysNamingConventionsConfig) getNameValidator() (*NameValidator, error) {
-	return getNameValidator(config.Format, config.CustomFormatKey, config)
+// validate compiles all custom formats, and builds the NameValidator of the config.
+func (config *terraformVarsObjectKeysNamingConventionsConfig) validate() error {
+	var err error
+	config.nameValidator, err = getNameValidator(config.Format, config.CustomFormatKey, config)
+	return err
 }
 
 // Builds the NameValidator according to `terraformVarsObjectKeysNamingConventionsConfig` struct
-// 1. If `format` is not "none", check in customFormats map first.
-// 2. If not found, will check with predefined formats (`snake_case`, `mixed_snake_case`); return error if no format is found.
+// 1. All regexes in customFormats are compiled, so that invalid ones are reported even if not used.
+// 2. If `format` is "none", return nil as no names are checked.
+// 3. If `custom_format_key` is specified, it must be found in customFormats.
+// 4. Otherwise, check with predefined formats (`snake_case`, `mixed_snake_case`).
 func getNameValidator(format string, customFormatKey string, config *terraformVarsObjectKeysNamingConventionsConfig) (*NameValidator, error) {
-	if format != "none" {
-		customFormats := config.CustomFormats
-		customFormatConfig, exists := customFormats[customFormatKey]
-
-		if exists {
-			return getCustomNameValidator(false, customFormatConfig.Description, customFormatConfig.Regexp)
+	customValidators := make(map[string]*NameValidator, len(config.CustomFormats))
+	for _, key := range slices.Sorted(maps.Keys(config.CustomFormats)) {
+		customFormatConfig := config.CustomFormats[key]
+		nameValidator, err := getCustomNameValidator(false, customFormatConfig.Description, customFormatConfig.Regexp)
+		if err != nil {
+			return nil, configErrorf(fmt.Sprintf("custom_formats[%q].regex", key), "%s", err)
 		}
+		customValidators[key] = nameValidator
+	}
 
-		regex, exists := predefinedFormats[strings.ToLower(format)]
-
-		if exists {
-			nameValidator := &NameValidator{
-				IsPredefinedFormat: true,
-				Format:             format,
-				Regexp:             regex,
-			}
+	if format == "none" {
+		return nil, nil
+	}
 
-			return nameValidator, nil
+	if customFormatKey != "" {
+		nameValidator, exists := customValidators[customFormatKey]
+		if !exists {
+			return nil, configErrorf("custom_format_key", "`%s` is not defined in custom_formats", customFormatKey)
 		}
+		return nameValidator, nil
+	}
 
-		return nil, fmt.Errorf("`%s` is unsupported format", format)
+	regex, exists := predefinedFormats[strings.ToLower(format)]
+	if !exists {
+		return nil, validateOneOf("format", format, slices.Concat(slices.Sorted(maps.Keys(predefinedFormats)), []string{"none"}))
 	}
 
-	return nil, nil
+	nameValidator := &NameValidator{
+		IsPredefinedFormat: true,
+		Format:             format,
+		Regexp:             regex,
+	}
+
+	return nameValidator, nil
 }
 
 // Creates a `NameValidator` struct from `expression` parameter regex string.
 func getCustomNameValidator(isNamed bool, format, expression string) (*NameValidator, error) {
 	regex, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
 
 	nameValidator := &NameValidator{
 		IsPredefinedFormat: isNamed,
@@ -240,7 +264,7 @@ func getCustomNameValidator(isNamed bool, format, expression string) (*NameValid
 		Regexp:             regex,
 	}
 
-	return nameValidator, err
+	return nameValidator, nil
 }
 
 // checkNestedObjectFields recursively validates that all object keys (field names) inside
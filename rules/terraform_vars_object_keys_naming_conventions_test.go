@@ -16,6 +16,20 @@ func Test_TerraformVarsObjectKeysNamingConventions(t *testing.T) {
 		Config   string
 		Expected helper.Issues
 	}{
+		// Test cases for `none`
+		{
+			Name: "no naming convention (none)",
+			Content: `
+variable "FooBar" {
+  type = object({
+    User_Name = string
+  })
+}
+`,
+			Config:   testTerraformVarsObjectKeysNamingConventions_none,
+			Expected: helper.Issues{},
+		},
+
 		// Test cases for `snake_case`
 		{
 			Name: "valid primitive type variable (snake_case)",
@@ -884,6 +898,83 @@ variable "ComplexConfig" {
 	}
 }
 
+func Test_TerraformVarsObjectKeysNamingConventions_JSON(t *testing.T) {
+	rule := NewTerraformVarsObjectKeysNamingConventions()
+
+	tests := []struct {
+		Name     string
+		Content  string
+		Config   string
+		Expected helper.Issues
+	}{
+		{
+			Name: "valid complex type - object variable (snake_case)",
+			Content: `{
+  "variable": {
+    "foo_bar": {
+      "type": "object({ id_number = string, user_name = string })"
+    }
+  }
+}`,
+			Config:   testTerraformVarsObjectKeysNamingConventions_snakeCase,
+			Expected: helper.Issues{},
+		},
+		{
+			Name: "invalid complex type - object variable (camelCase name and keys)",
+			Content: `{
+  "variable": {
+    "fooBar": {
+      "type": "map(object({ idNumber = string }))"
+    }
+  }
+}`,
+			Config: testTerraformVarsObjectKeysNamingConventions_snakeCase,
+			Expected: helper.Issues{
+				{
+					Rule:    rule,
+					Message: "variable `fooBar` must match the following predefined_format: snake_case",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 3, Column: 15},
+						End:      hcl.Pos{Line: 3, Column: 16},
+					},
+				},
+				{
+					Rule:    rule,
+					Message: "variable `fooBar` path `fooBar.idNumber` - attribute `idNumber` must match the following predefined_format: snake_case",
+					Range: hcl.Range{
+						Filename: "main.tf.json",
+						Start:    hcl.Pos{Line: 4, Column: 7},
+						End:      hcl.Pos{Line: 4, Column: 51},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runner := helper.TestRunner(t, map[string]string{
+				"main.tf.json": test.Content,
+				".tflint.hcl":  test.Config,
+			})
+
+			if err := rule.Check(runner); err != nil {
+				t.Fatalf("Unexpected error occurred: %s", err)
+			}
+
+			helper.AssertIssues(t, test.Expected, runner.Issues)
+		})
+	}
+}
+
+const testTerraformVarsObjectKeysNamingConventions_none = `
+rule "terraform_vars_object_keys_naming_conventions" {
+  enabled = true
+  format  = "none"
+}
+`
+
 const testTerraformVarsObjectKeysNamingConventions_snakeCase = `
 rule "terraform_vars_object_keys_naming_conventions" {
   enabled = true
@@ -0,0 +1,114 @@
+// Command taggable-resources writes the resource types supporting tags, from the
+// output of `terraform providers schema -json`, to the list embedded in the
+// terraform_required_tags rule.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/myklst/tflint-ruleset-myklst/providerschema"
+)
+
+// tagAttributes are the attributes of tags by provider type, which are the default tag
+// attributes of the terraform_required_tags rule.
+var tagAttributes = map[string]string{
+	"alicloud": "tags",
+	"aws":      "tags",
+	"azurerm":  "tags",
+	"google":   "labels",
+}
+
+func main() {
+	schemaFile := flag.String("schema", "schema.json", "output of `terraform providers schema -json`")
+	lockFile := flag.String("lock", ".terraform.lock.hcl", "dependency lock file recording the provider versions of the schemas")
+	output := flag.String("output", "rules/taggable_resources.txt", "file to write the resource types")
+	flag.Parse()
+
+	if err := run(*schemaFile, *lockFile, *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(schemaFile, lockFile, output string) error {
+	schemas, err := providerschema.Read(schemaFile)
+	if err != nil {
+		return err
+	}
+	versions, err := readProviderVersions(lockFile)
+	if err != nil {
+		return err
+	}
+
+	var resources []string
+	for source, provider := range schemas.ProviderSchemas {
+		attribute, exists := tagAttributes[path.Base(source)]
+		if !exists {
+			continue
+		}
+		if _, exists := versions[source]; !exists {
+			return fmt.Errorf("provider %s is not found in %s", source, lockFile)
+		}
+		for resourceType, schema := range provider.ResourceSchemas {
+			if schema.Block != nil && schema.Block.HasPath([]string{attribute}) {
+				resources = append(resources, resourceType)
+			}
+		}
+	}
+	if len(resources) == 0 {
+		return fmt.Errorf("no taggable resource types in %s", schemaFile)
+	}
+	slices.Sort(resources)
+
+	var b strings.Builder
+	b.WriteString("# Resource types which set tags in `tags`, or `labels` for Google Cloud.\n")
+	b.WriteString("# Generated from the provider schemas by `make taggable-resources`. DO NOT EDIT.\n")
+	b.WriteString("# Provider versions, pinned in tools/taggable-resources/providers.tf:\n")
+	for _, source := range slices.Sorted(maps.Keys(schemas.ProviderSchemas)) {
+		if _, exists := tagAttributes[path.Base(source)]; exists {
+			b.WriteString(fmt.Sprintf("#   %s %s\n", source, versions[source]))
+		}
+	}
+	for _, resourceType := range resources {
+		b.WriteString(resourceType + "\n")
+	}
+	return os.WriteFile(output, []byte(b.String()), 0o644)
+}
+
+// lockFile is the dependency lock file written by `terraform init`. Only the versions
+// of the providers are decoded.
+type lockFile struct {
+	Providers []struct {
+		Source  string   `hcl:"source,label"`
+		Version string   `hcl:"version"`
+		Remain  hcl.Body `hcl:",remain"`
+	} `hcl:"provider,block"`
+}
+
+// readProviderVersions returns the versions of the providers selected by `terraform init`,
+// keyed by their source address, as the schemas do not include them.
+func readProviderVersions(filename string) (map[string]string, error) {
+	file, diags := hclparse.NewParser().ParseHCLFile(filename)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	var lock lockFile
+	if diags := gohcl.DecodeBody(file.Body, nil, &lock); diags.HasErrors() {
+		return nil, diags
+	}
+
+	versions := map[string]string{}
+	for _, provider := range lock.Providers {
+		versions[provider.Source] = provider.Version
+	}
+	return versions, nil
+}
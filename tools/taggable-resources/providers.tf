@@ -0,0 +1,23 @@
+# Providers whose resource types are listed in rules/taggable_resources.txt. The versions
+# are pinned, so that `make taggable-resources` generates the same list until they are
+# bumped here.
+terraform {
+  required_providers {
+    alicloud = {
+      source  = "aliyun/alicloud"
+      version = "= 1.238.0"
+    }
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.80.0"
+    }
+    azurerm = {
+      source  = "hashicorp/azurerm"
+      version = "= 4.14.0"
+    }
+    google = {
+      source  = "hashicorp/google"
+      version = "= 6.14.0"
+    }
+  }
+}